@@ -17,11 +17,21 @@ limitations under the License.
 package v1alpha1
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/operator-framework/operator-controller/internal/conditionsets"
 )
 
+//+kubebuilder:validation:MaxLength:=48
+//+kubebuilder:validation:Pattern:=^[a-z0-9]+([\.-][a-z0-9]+)*$
+
+// ChannelName is the name of a package channel.
+type ChannelName string
+
+//+kubebuilder:validation:XValidation:rule="!(has(self.version) && has(self.versionPreference))",message="version and versionPreference cannot both be set"
+//+kubebuilder:validation:XValidation:rule="!(has(self.channel) && has(self.channels))",message="channel and channels cannot both be set"
+
 // OperatorSpec defines the desired state of Operator
 type OperatorSpec struct {
 	//+kubebuilder:validation:MaxLength:=48
@@ -38,10 +48,275 @@ type OperatorSpec struct {
 	// For more information on semver, please see https://semver.org/
 	Version string `json:"version,omitempty"`
 
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxItems:=8
+	//+kubebuilder:validation:XValidation:rule="self.all(r, size(r) <= 64)",message="versionPreference entries may not be longer than 64 characters"
+	// VersionPreference is an ordered list of semver ranges the package version must be in.
+	// Bundles in an earlier range are preferred over bundles in a later one, so
+	// ["2.x", "1.9.x"] installs the latest 2.x release if one can be installed, and
+	// otherwise the latest 1.9.x release. It cannot be combined with version.
+	VersionPreference []string `json:"versionPreference,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxItems:=32
+	// VersionExcludes is a list of versions and semver ranges that are never installed,
+	// even if they match version or versionPreference, e.g. ["1.2.3", ">=1.4.0 <1.4.2"]
+	// to skip known-bad releases. Resolution fails with a message listing the excluded
+	// versions if every otherwise matching bundle is excluded.
+	VersionExcludes []string `json:"versionExcludes,omitempty"`
+
 	//+kubebuilder:validation:MaxLength:=48
 	//+kubebuilder:validation:Pattern:=^[a-z0-9]+([\.-][a-z0-9]+)*$
 	// Channel constraint defintion
 	Channel string `json:"channel,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxItems:=8
+	//+listType=set
+	// Channels is an ordered list of channels to resolve the package from. Bundles from an
+	// earlier channel are preferred, and a later channel is only used when no bundle from the
+	// earlier ones can be installed. The channel of the resolved bundle is reported in
+	// status.resolvedChannel. It cannot be combined with channel.
+	Channels []ChannelName `json:"channels,omitempty"`
+
+	//+kubebuilder:Optional
+	// Catalog optionally restricts resolution to bundles from a single Catalog.
+	// If catalog.ref is also set, resolution only proceeds while the Catalog's content
+	// was unpacked from that image reference, which makes resolution reproducible until
+	// the pin is explicitly advanced.
+	Catalog *CatalogReference `json:"catalog,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxItems:=32
+	//+listType=set
+	// DependsOn lists the names of other Operators that must report Installed before
+	// this Operator's bundle is installed or upgraded. Resolution is not affected.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:Minimum:=1
+	// ProgressDeadlineSeconds is the number of seconds the bundle may take to report
+	// Installed after its BundleDeployment is applied. Once the deadline passes, the
+	// Installed condition is set to False with reason ProgressDeadlineExceeded until the
+	// bundle is installed or a different bundle is applied. There is no deadline if unset.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Direct;Sequential
+	//+kubebuilder:Optional
+	// UpgradeStrategy controls how the Operator is upgraded to a resolved bundle that
+	// does not upgrade directly from the installed bundle. Direct, the default, installs
+	// the resolved bundle right away. Sequential installs each bundle on the shortest
+	// upgrade path through the resolved bundle's channel in turn, and only moves on to
+	// the next bundle once the previous one is installed. If the channel has no upgrade
+	// path from the installed bundle, nothing is applied and the Installed condition
+	// reason is set to NoUpgradePath. Sequential requires the SequentialUpgradeStrategy
+	// feature gate.
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Automatic;ZStream
+	//+kubebuilder:Optional
+	// UpgradePolicy controls which upgrades are applied automatically. Automatic, the
+	// default, upgrades to the latest bundle that can be installed. ZStream only upgrades
+	// to bundles with the major and minor version of the installed bundle; upgrading to a
+	// new minor or major version requires setting version. ZStream has no effect while
+	// version or versionPreference is set, or while the installed bundle is not in a catalog.
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Enforce;AllowSkip
+	//+kubebuilder:Optional
+	// UpgradeConstraintPolicy controls upgrades that skip versions of the resolved bundle's
+	// channel, e.g. by following a skipRange or with the Direct upgrade strategy. Enforce
+	// does not apply such upgrades, and sets the Installed condition reason to
+	// SkipNotAcknowledged until the policy is changed to AllowSkip. AllowSkip applies them
+	// and records the skipped versions in status.skippedVersions. If unset, upgrades are
+	// not checked for skipped versions. Requires the UpgradeConstraintPolicy feature gate.
+	UpgradeConstraintPolicy UpgradeConstraintPolicy `json:"upgradeConstraintPolicy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Delete;Block
+	//+kubebuilder:Optional
+	// DeletionPolicy controls whether deleting the Operator is checked for
+	// dependents first. Delete, the default, deletes the Operator right away. Block
+	// holds the Operator with the DeletionProtectionFinalizer while other Operators
+	// depend on it or, with the ProvidedAPIIndex feature gate, instances of the APIs
+	// it provides exist. The Installed condition
+	// reason is DeletionBlocked while deletion is blocked. Instances are listed with the
+	// access granted by ClusterRoles labeled
+	// operators.operatorframework.io/aggregate-to-deletion-protection=true, and deletion
+	// stays blocked for provided APIs that can't be listed.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Refuse;Adopt
+	//+kubebuilder:Optional
+	// AdoptionPolicy controls what happens when a BundleDeployment named after the
+	// Operator exists that the Operator does not control, e.g. one created by hand.
+	// Refuse, the default, leaves it untouched and sets the Installed condition reason to
+	// OwnershipConflict. Adopt takes it over, unless another object controls it.
+	AdoptionPolicy AdoptionPolicy `json:"adoptionPolicy,omitempty"`
+
+	//+kubebuilder:Optional
+	// BundleOverride installs the given bundle in place of the resolved bundle, e.g. to
+	// roll out a hotfix that is not in any catalog yet. Resolution still runs and is
+	// reported in status. The Installed condition reason is Overridden while the override
+	// is set; removing it installs the resolved bundle again. Requires the BundleOverride
+	// feature gate.
+	BundleOverride *BundleOverride `json:"bundleOverride,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxItems:=16
+	//+listType=map
+	//+listMapKey=name
+	// LifecycleHooks are Jobs run before a bundle is installed or upgraded to, and after it
+	// is installed. Each hook runs once per bundle. Requires the LifecycleHooks feature gate.
+	LifecycleHooks []LifecycleHook `json:"lifecycleHooks,omitempty"`
+}
+
+// LifecycleHookPhase is when a lifecycle hook runs.
+type LifecycleHookPhase string
+
+const (
+	// LifecycleHookPreInstall hooks run before a bundle is applied. The bundle
+	// is only applied once they have succeeded.
+	LifecycleHookPreInstall LifecycleHookPhase = "PreInstall"
+	// LifecycleHookPostInstall hooks run once a bundle is installed.
+	LifecycleHookPostInstall LifecycleHookPhase = "PostInstall"
+)
+
+// LifecycleHookFailurePolicy is how the failure of a lifecycle hook is handled.
+type LifecycleHookFailurePolicy string
+
+const (
+	// LifecycleHookFailurePolicyFail sets the Installed condition to False.
+	// A failed PreInstall hook also keeps the bundle from being applied.
+	LifecycleHookFailurePolicyFail LifecycleHookFailurePolicy = "Fail"
+	// LifecycleHookFailurePolicyIgnore treats a failed hook as succeeded.
+	LifecycleHookFailurePolicyIgnore LifecycleHookFailurePolicy = "Ignore"
+)
+
+// LifecycleHook is a Job run before or after a bundle is installed.
+type LifecycleHook struct {
+	//+kubebuilder:validation:MaxLength:=32
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]+(-[a-z0-9]+)*$
+	// Name identifies the hook.
+	Name string `json:"name"`
+
+	//+kubebuilder:validation:Enum:=PreInstall;PostInstall
+	// Phase is when the hook runs.
+	Phase LifecycleHookPhase `json:"phase"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:Minimum:=1
+	// TimeoutSeconds is the time the hook's Job may run before it is failed. It is set
+	// as the Job's activeDeadlineSeconds.
+	TimeoutSeconds *int64 `json:"timeoutSeconds,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:Enum:=Fail;Ignore
+	// FailurePolicy is how a failure of the hook is handled. Defaults to Fail.
+	FailurePolicy LifecycleHookFailurePolicy `json:"failurePolicy,omitempty"`
+
+	//+kubebuilder:validation:Schemaless
+	//+kubebuilder:pruning:PreserveUnknownFields
+	//+kubebuilder:validation:Type:=object
+	// Job is the spec of the hook's Job. The Job is created in the namespace
+	// the cluster admin configured for lifecycle hooks, and its pods run as the
+	// configured service account; a hook whose pod template names another
+	// service account fails without running.
+	Job batchv1.JobSpec `json:"job"`
+}
+
+//+kubebuilder:validation:XValidation:rule="has(self.image) != has(self.configMap)",message="exactly one of image or configMap must be set"
+
+// BundleOverride is a bundle installed in place of an Operator's resolved bundle.
+// Exactly one of Image or ConfigMap must be set.
+type BundleOverride struct {
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxLength:=1024
+	// Image is a bundle image reference.
+	Image string `json:"image,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxLength:=253
+	// ConfigMap is the name of a ConfigMap in the rukpak system namespace that holds
+	// the bundle's manifests.
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// DeletionPolicy is how the deletion of an Operator is handled.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the Operator without checking for dependents.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+	// DeletionPolicyBlock blocks deletion of the Operator while it has dependents.
+	DeletionPolicyBlock DeletionPolicy = "Block"
+)
+
+// AdoptionPolicy is how an existing BundleDeployment that an Operator does not
+// control is handled.
+type AdoptionPolicy string
+
+const (
+	// AdoptionPolicyRefuse leaves the BundleDeployment untouched.
+	AdoptionPolicyRefuse AdoptionPolicy = "Refuse"
+	// AdoptionPolicyAdopt takes over the BundleDeployment if nothing controls it.
+	AdoptionPolicyAdopt AdoptionPolicy = "Adopt"
+)
+
+// DeletionProtectionFinalizer is set on Operators with the Block deletion
+// policy and removed once the Operator has no dependents.
+const DeletionProtectionFinalizer = "operators.operatorframework.io/deletion-protection"
+
+// ReconcileRequestAnnotation requests a repair of an Operator when it is set
+// to a new value, for example the current time. The Operator is resolved
+// again and its BundleDeployment is re-applied even if it looks up to date.
+// The value is recorded in status.lastHandledReconcileRequest once handled.
+const ReconcileRequestAnnotation = "operators.operatorframework.io/reconcile"
+
+// UpgradeStrategy is how an Operator is upgraded to its resolved bundle.
+type UpgradeStrategy string
+
+const (
+	// UpgradeStrategyDirect installs the resolved bundle right away.
+	UpgradeStrategyDirect UpgradeStrategy = "Direct"
+	// UpgradeStrategySequential installs every bundle on the upgrade path
+	// to the resolved bundle in turn.
+	UpgradeStrategySequential UpgradeStrategy = "Sequential"
+)
+
+// UpgradePolicy is which upgrades of an Operator are applied automatically.
+type UpgradePolicy string
+
+const (
+	// UpgradePolicyAutomatic applies every upgrade.
+	UpgradePolicyAutomatic UpgradePolicy = "Automatic"
+	// UpgradePolicyZStream only applies upgrades within the installed minor version.
+	UpgradePolicyZStream UpgradePolicy = "ZStream"
+)
+
+// UpgradeConstraintPolicy is how upgrades that skip versions are handled.
+type UpgradeConstraintPolicy string
+
+const (
+	// UpgradeConstraintPolicyEnforce refuses upgrades that skip versions.
+	UpgradeConstraintPolicyEnforce UpgradeConstraintPolicy = "Enforce"
+	// UpgradeConstraintPolicyAllowSkip acknowledges upgrades that skip versions.
+	UpgradeConstraintPolicyAllowSkip UpgradeConstraintPolicy = "AllowSkip"
+)
+
+// CatalogReference identifies a Catalog and, optionally, a snapshot of its content.
+type CatalogReference struct {
+	//+kubebuilder:validation:MaxLength:=253
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]([-a-z0-9]*[a-z0-9])?([.][a-z0-9]([-a-z0-9]*[a-z0-9])?)*$
+	// Name is the name of the Catalog to resolve bundles from.
+	Name string `json:"name"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:MaxLength:=1024
+	//+kubebuilder:validation:XValidation:rule="self.contains('@sha256:')",message="catalog ref must be a digest reference"
+	// Ref pins resolution to the Catalog content unpacked from this image reference,
+	// as reported in the Catalog's status.resolvedSource.image.ref. A digest reference
+	// is expected, e.g. quay.io/operatorhubio/catalog@sha256:...
+	Ref string `json:"ref,omitempty"`
 }
 
 const (
@@ -49,14 +324,40 @@ const (
 	TypeInstalled = "Installed"
 	TypeResolved  = "Resolved"
 
-	ReasonBundleLookupFailed        = "BundleLookupFailed"
-	ReasonInstallationFailed        = "InstallationFailed"
-	ReasonInstallationStatusUnknown = "InstallationStatusUnknown"
-	ReasonInstallationSucceeded     = "InstallationSucceeded"
-	ReasonInvalidSpec               = "InvalidSpec"
-	ReasonResolutionFailed          = "ResolutionFailed"
-	ReasonResolutionUnknown         = "ResolutionUnknown"
-	ReasonSuccess                   = "Success"
+	ReasonBundleLookupFailed         = "BundleLookupFailed"
+	ReasonCatalogUnavailable         = "CatalogUnavailable"
+	ReasonCatalogSnapshotUnavailable = "CatalogSnapshotUnavailable"
+	ReasonDeletionBlocked            = "DeletionBlocked"
+	ReasonHookFailed                 = "HookFailed"
+	ReasonInstallationFailed         = "InstallationFailed"
+	ReasonInstallationStatusUnknown  = "InstallationStatusUnknown"
+	ReasonInstallationSucceeded      = "InstallationSucceeded"
+	ReasonInvalidSpec                = "InvalidSpec"
+	ReasonNoUpgradePath              = "NoUpgradePath"
+	ReasonOverridden                 = "Overridden"
+	ReasonOwnershipConflict          = "OwnershipConflict"
+	ReasonPolicyViolation            = "PolicyViolation"
+	ReasonProgressDeadlineExceeded   = "ProgressDeadlineExceeded"
+	ReasonResolutionFailed           = "ResolutionFailed"
+	ReasonResolutionRegressed        = "ResolutionRegressed"
+	ReasonResolutionUnknown          = "ResolutionUnknown"
+	ReasonSkipNotAcknowledged        = "SkipNotAcknowledged"
+	ReasonSuccess                    = "Success"
+	ReasonWaitingForDependencies     = "WaitingForDependencies"
+	ReasonWaitingForHooks            = "WaitingForHooks"
+)
+
+// Phases summarize an Operator's conditions in status.phase.
+const (
+	// PhaseProgressing means the Operator has not been installed yet and
+	// nothing has failed, e.g. installation is in progress or is waiting for
+	// its dependencies, its catalog or its pinned catalog snapshot.
+	PhaseProgressing = "Progressing"
+	// PhaseInstalled means the resolved bundle is installed.
+	PhaseInstalled = "Installed"
+	// PhaseFailed means resolution or installation failed, or the spec is
+	// invalid. The conditions describe the failure.
+	PhaseFailed = "Failed"
 )
 
 func init() {
@@ -71,19 +372,82 @@ func init() {
 		ReasonResolutionFailed,
 		ReasonResolutionUnknown,
 		ReasonBundleLookupFailed,
+		ReasonCatalogUnavailable,
+		ReasonCatalogSnapshotUnavailable,
+		ReasonDeletionBlocked,
+		ReasonHookFailed,
 		ReasonInstallationFailed,
 		ReasonInstallationStatusUnknown,
 		ReasonInvalidSpec,
+		ReasonNoUpgradePath,
+		ReasonOverridden,
+		ReasonOwnershipConflict,
+		ReasonPolicyViolation,
+		ReasonProgressDeadlineExceeded,
+		ReasonResolutionRegressed,
+		ReasonSkipNotAcknowledged,
 		ReasonSuccess,
+		ReasonWaitingForDependencies,
+		ReasonWaitingForHooks,
 	)
 }
 
 // OperatorStatus defines the observed state of Operator
 type OperatorStatus struct {
+	// +optional
+	// Phase summarizes the Installed and Resolved conditions for tools that
+	// need a single health value, such as GitOps health checks. It is one of
+	// Progressing, Installed or Failed and is derived from conditions whose
+	// observedGeneration matches the Operator's generation.
+	Phase string `json:"phase,omitempty"`
 	// +optional
 	InstalledBundleResource string `json:"installedBundleResource,omitempty"`
 	// +optional
+	// InstalledBundleProvenance identifies the catalog content the installed
+	// bundle was resolved from, so that its workloads can be traced back to
+	// the exact bundle artifact.
+	InstalledBundleProvenance *BundleProvenance `json:"installedBundleProvenance,omitempty"`
+	// +optional
 	ResolvedBundleResource string `json:"resolvedBundleResource,omitempty"`
+	// +optional
+	// ResolvedChannel is the channel the resolved bundle was resolved from.
+	ResolvedChannel string `json:"resolvedChannel,omitempty"`
+	// +optional
+	// ProvidedAPIs are the APIs the resolved bundle provides, as declared by its
+	// olm.gvk properties.
+	ProvidedAPIs []metav1.GroupVersionKind `json:"providedAPIs,omitempty"`
+	// +optional
+	// UpgradePath lists, in order, the bundles that remain to be installed while a
+	// Sequential upgrade is in progress. The first bundle is the one being installed
+	// and the last is the resolved bundle.
+	UpgradePath []string `json:"upgradePath,omitempty"`
+	// +optional
+	// SkippedVersions lists the versions of the channel skipped by the most recent
+	// upgrade that was allowed with upgradeConstraintPolicy AllowSkip.
+	SkippedVersions []string `json:"skippedVersions,omitempty"`
+	// +optional
+	// DefaultsConflicts lists the spec fields the Operator leaves unset that several
+	// OperatorDefaults selecting it set to different values, and which value is used.
+	DefaultsConflicts []string `json:"defaultsConflicts,omitempty"`
+	// +optional
+	// ResolutionHistory records, oldest first, the most recent bundles that were resolved
+	// but not installed, and why. An entry is added when the resolved bundle, its catalog
+	// content or the reason it is not installed changes. At most 10 entries are kept.
+	ResolutionHistory []ResolutionDecision `json:"resolutionHistory,omitempty"`
+	// +optional
+	// LastHandledReconcileRequest is the last value of the
+	// operators.operatorframework.io/reconcile annotation that was handled.
+	LastHandledReconcileRequest string `json:"lastHandledReconcileRequest,omitempty"`
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// LifecycleHooks reports the most recent run of each lifecycle hook.
+	LifecycleHooks []LifecycleHookStatus `json:"lifecycleHooks,omitempty"`
+	// +optional
+	// UpgradeGraph is the part of the catalog's upgrade graph around the resolved
+	// bundle. It is only set when the catalog the bundle was resolved from
+	// publishes channel information for the package.
+	UpgradeGraph *UpgradeGraph `json:"upgradeGraph,omitempty"`
 
 	// +patchMergeKey=type
 	// +patchStrategy=merge
@@ -92,9 +456,95 @@ type OperatorStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// LifecycleHookState is the state of a lifecycle hook run.
+type LifecycleHookState string
+
+const (
+	// LifecycleHookRunning means the hook's Job has not finished yet.
+	LifecycleHookRunning LifecycleHookState = "Running"
+	// LifecycleHookSucceeded means the hook's Job completed.
+	LifecycleHookSucceeded LifecycleHookState = "Succeeded"
+	// LifecycleHookFailed means the hook's Job failed.
+	LifecycleHookFailed LifecycleHookState = "Failed"
+)
+
+// LifecycleHookStatus is the state of a lifecycle hook's run for a bundle.
+type LifecycleHookStatus struct {
+	// Name is the name of the hook.
+	Name string `json:"name"`
+	// Bundle is the bundle the hook ran for.
+	Bundle string `json:"bundle"`
+	// Job is the name of the hook's Job.
+	Job string `json:"job"`
+	// State is one of Running, Succeeded or Failed.
+	State LifecycleHookState `json:"state"`
+	// +optional
+	// Message describes why the hook failed.
+	Message string `json:"message,omitempty"`
+}
+
+// ResolutionDecision is a bundle that was resolved for an Operator but not installed.
+type ResolutionDecision struct {
+	// Time is when the bundle was first resolved and not installed for Reason.
+	Time metav1.Time `json:"time"`
+	// Bundle is the image reference of the resolved bundle.
+	Bundle string `json:"bundle"`
+	// +optional
+	// Catalog is the name of the Catalog the bundle was resolved from.
+	Catalog string `json:"catalog,omitempty"`
+	// +optional
+	// CatalogRef is the resolved image reference of the catalog content the
+	// bundle was resolved from.
+	CatalogRef string `json:"catalogRef,omitempty"`
+	// Reason is the reason of the Installed condition, e.g. WaitingForHooks or
+	// SkipNotAcknowledged.
+	Reason string `json:"reason"`
+	// +optional
+	// Message is the most recent message of the Installed condition.
+	Message string `json:"message,omitempty"`
+}
+
+// BundleProvenance identifies a bundle artifact and the catalog content it
+// was resolved from.
+type BundleProvenance struct {
+	// Image is the bundle image reference.
+	Image string `json:"image"`
+	// +optional
+	// Digest is the digest of the bundle image, if the image is referenced by digest.
+	Digest string `json:"digest,omitempty"`
+	// +optional
+	// Catalog is the name of the Catalog the bundle was resolved from.
+	Catalog string `json:"catalog,omitempty"`
+	// +optional
+	// CatalogRef is the resolved image reference of the catalog content the
+	// bundle was resolved from.
+	CatalogRef string `json:"catalogRef,omitempty"`
+}
+
+// UpgradeGraph describes the upgrade edges around the resolved bundle in its channel.
+type UpgradeGraph struct {
+	// Channel is the channel the resolved bundle was resolved from.
+	Channel string `json:"channel"`
+	// Current is the name of the resolved bundle.
+	Current string `json:"current"`
+	// +optional
+	// Successors are the bundles in the channel that upgrade directly from the
+	// resolved bundle, because they replace or skip it or its version is in
+	// their skip range.
+	Successors []string `json:"successors,omitempty"`
+	// +optional
+	// Heads are the bundles in the channel that no other bundle replaces or skips.
+	Heads []string `json:"heads,omitempty"`
+}
+
 //+kubebuilder:object:root=true
 //+kubebuilder:resource:scope=Cluster
 //+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Package",type=string,JSONPath=`.spec.packageName`
+//+kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+//+kubebuilder:printcolumn:name="Installed Bundle",type=string,JSONPath=`.status.installedBundleResource`,priority=1
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+//+kubebuilder:validation:XValidation:rule="!has(self.spec) || !has(self.spec.dependsOn) || !(self.metadata.name in self.spec.dependsOn)",message="spec.dependsOn must not contain the operator itself"
 
 // Operator is the Schema for the operators API
 type Operator struct {
@@ -0,0 +1,80 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorDefaultsSpec defines the desired state of OperatorDefaults
+type OperatorDefaultsSpec struct {
+	//+kubebuilder:Optional
+	// OperatorSelector selects the Operators the defaults apply to by their labels.
+	// An empty selector selects every Operator.
+	OperatorSelector metav1.LabelSelector `json:"operatorSelector,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Direct;Sequential
+	//+kubebuilder:Optional
+	// UpgradeStrategy is used for selected Operators that do not set spec.upgradeStrategy.
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Automatic;ZStream
+	//+kubebuilder:Optional
+	// UpgradePolicy is used for selected Operators that do not set spec.upgradePolicy.
+	UpgradePolicy UpgradePolicy `json:"upgradePolicy,omitempty"`
+
+	//+kubebuilder:validation:Enum:=Delete;Block
+	//+kubebuilder:Optional
+	// DeletionPolicy is used for selected Operators that do not set spec.deletionPolicy.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	//+kubebuilder:Optional
+	//+kubebuilder:validation:Minimum:=1
+	// ProgressDeadlineSeconds is used for selected Operators that do not set
+	// spec.progressDeadlineSeconds.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+
+// OperatorDefaults holds defaults for the spec of the Operators it selects. An
+// Operator's own spec fields always take precedence. When several
+// OperatorDefaults select an Operator and set the same field, the one whose
+// name sorts first is used, and differing values are reported in the
+// Operator's status.defaultsConflicts. Defaults are not written to the
+// Operator; they are applied whenever it is reconciled, so changes take effect
+// on every selected Operator.
+type OperatorDefaults struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OperatorDefaultsSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OperatorDefaultsList contains a list of OperatorDefaults
+type OperatorDefaultsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorDefaults `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorDefaults{}, &OperatorDefaultsList{})
+}
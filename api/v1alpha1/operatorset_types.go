@@ -0,0 +1,130 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OperatorSetLabel is set on every Operator created for an OperatorSet to the
+// name of the OperatorSet.
+const OperatorSetLabel = "operators.operatorframework.io/operator-set"
+
+// OperatorSetSpec defines the desired state of OperatorSet
+type OperatorSetSpec struct {
+	//+kubebuilder:Optional
+	// Template holds the values shared by every Operator created for the set.
+	Template OperatorSetTemplate `json:"template,omitempty"`
+
+	//+kubebuilder:validation:MinItems:=1
+	//+kubebuilder:validation:MaxItems:=64
+	//+listType=map
+	//+listMapKey=packageName
+	// Packages lists the packages to install. An Operator named <set name>-<package name>
+	// is created for each entry; Operators for entries that are removed are deleted.
+	Packages []OperatorSetPackage `json:"packages"`
+}
+
+// OperatorSetTemplate holds the values applied to every Operator in an OperatorSet.
+type OperatorSetTemplate struct {
+	//+kubebuilder:Optional
+	// Labels are added to every Operator in the set.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	//+kubebuilder:Optional
+	// Annotations are added to every Operator in the set.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=48
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]+([\.-][a-z0-9]+)*$
+	//+kubebuilder:Optional
+	// Channel is used for packages that do not specify their own channel.
+	Channel string `json:"channel,omitempty"`
+
+	//+kubebuilder:Optional
+	// Catalog is set on every Operator in the set.
+	Catalog *CatalogReference `json:"catalog,omitempty"`
+}
+
+// OperatorSetPackage is a package installed by an OperatorSet.
+type OperatorSetPackage struct {
+	//+kubebuilder:validation:MaxLength:=48
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]+(-[a-z0-9]+)*$
+	PackageName string `json:"packageName"`
+
+	//+kubebuilder:validation:MaxLength:=64
+	//+kubebuilder:validation:Pattern=^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-(0|[1-9]\d*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*)(\.(0|[1-9]\d*|[0-9]*[a-zA-Z-][0-9a-zA-Z-]*))*)?(\+([0-9a-zA-Z-]+(\.[0-9a-zA-Z-]+)*))?$
+	//+kubebuilder:Optional
+	// Version is passed to the package's Operator as spec.version.
+	Version string `json:"version,omitempty"`
+
+	//+kubebuilder:validation:MaxLength:=48
+	//+kubebuilder:validation:Pattern:=^[a-z0-9]+([\.-][a-z0-9]+)*$
+	//+kubebuilder:Optional
+	// Channel overrides the template channel for this package.
+	Channel string `json:"channel,omitempty"`
+}
+
+// OperatorSetStatus defines the observed state of OperatorSet
+type OperatorSetStatus struct {
+	// +optional
+	// Operators reports the state of every Operator in the set.
+	Operators []OperatorSetMemberStatus `json:"operators,omitempty"`
+
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+}
+
+// OperatorSetMemberStatus is the observed state of an Operator in an OperatorSet.
+type OperatorSetMemberStatus struct {
+	Name        string `json:"name"`
+	PackageName string `json:"packageName"`
+	// +optional
+	InstalledBundleResource string `json:"installedBundleResource,omitempty"`
+	// Installed is the status of the Operator's Installed condition.
+	Installed metav1.ConditionStatus `json:"installed"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+
+// OperatorSet is the Schema for the operatorsets API. OperatorSets are only
+// reconciled when the OperatorSets feature gate is enabled.
+type OperatorSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorSetSpec   `json:"spec,omitempty"`
+	Status OperatorSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// OperatorSetList contains a list of OperatorSet
+type OperatorSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorSet{}, &OperatorSetList{})
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ProvidedAPISpec identifies an API by group and kind.
+type ProvidedAPISpec struct {
+	// Group is the API group. It is empty for the core group.
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind"`
+}
+
+// ProvidedAPIStatus defines the observed state of ProvidedAPI
+type ProvidedAPIStatus struct {
+	// +optional
+	// Providers lists the installed Operators whose bundles provide the API,
+	// sorted by Operator name. More than one provider indicates a conflict.
+	Providers []APIProvider `json:"providers,omitempty"`
+}
+
+// APIProvider is an installed Operator that provides an API.
+type APIProvider struct {
+	// Operator is the name of the Operator.
+	Operator string `json:"operator"`
+	// InstalledBundleResource is the bundle installed by the Operator.
+	InstalledBundleResource string `json:"installedBundleResource"`
+	// Versions are the versions of the API provided by the bundle.
+	Versions []string `json:"versions"`
+}
+
+// ProvidedAPIName returns the name of the ProvidedAPI for group and kind,
+// i.e. <lowercase kind>.<group>, or <lowercase kind>.core for the core group.
+func ProvidedAPIName(group, kind string) string {
+	if group == "" {
+		group = "core"
+	}
+	return strings.ToLower(kind) + "." + group
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Group",type=string,JSONPath=`.spec.group`
+//+kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.kind`
+//+kubebuilder:printcolumn:name="Operators",type=string,JSONPath=`.status.providers[*].operator`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ProvidedAPI records which installed Operators provide an API. ProvidedAPIs
+// are maintained by operator-controller from the olm.gvk properties of the
+// installed bundles when the ProvidedAPIIndex feature gate is enabled, and
+// should not be modified.
+type ProvidedAPI struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ProvidedAPISpec   `json:"spec,omitempty"`
+	Status ProvidedAPIStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ProvidedAPIList contains a list of ProvidedAPI
+type ProvidedAPIList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ProvidedAPI `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ProvidedAPI{}, &ProvidedAPIList{})
+}
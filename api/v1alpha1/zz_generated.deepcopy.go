@@ -26,12 +26,113 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIProvider) DeepCopyInto(out *APIProvider) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIProvider.
+func (in *APIProvider) DeepCopy() *APIProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(APIProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleOverride) DeepCopyInto(out *BundleOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleOverride.
+func (in *BundleOverride) DeepCopy() *BundleOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleProvenance) DeepCopyInto(out *BundleProvenance) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleProvenance.
+func (in *BundleProvenance) DeepCopy() *BundleProvenance {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleProvenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogReference) DeepCopyInto(out *CatalogReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogReference.
+func (in *CatalogReference) DeepCopy() *CatalogReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHook) DeepCopyInto(out *LifecycleHook) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	in.Job.DeepCopyInto(&out.Job)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHook.
+func (in *LifecycleHook) DeepCopy() *LifecycleHook {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LifecycleHookStatus) DeepCopyInto(out *LifecycleHookStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LifecycleHookStatus.
+func (in *LifecycleHookStatus) DeepCopy() *LifecycleHookStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LifecycleHookStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Operator) DeepCopyInto(out *Operator) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -53,6 +154,85 @@ func (in *Operator) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorDefaults) DeepCopyInto(out *OperatorDefaults) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorDefaults.
+func (in *OperatorDefaults) DeepCopy() *OperatorDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorDefaults) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorDefaultsList) DeepCopyInto(out *OperatorDefaultsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorDefaults, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorDefaultsList.
+func (in *OperatorDefaultsList) DeepCopy() *OperatorDefaultsList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorDefaultsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorDefaultsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorDefaultsSpec) DeepCopyInto(out *OperatorDefaultsSpec) {
+	*out = *in
+	in.OperatorSelector.DeepCopyInto(&out.OperatorSelector)
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorDefaultsSpec.
+func (in *OperatorDefaultsSpec) DeepCopy() *OperatorDefaultsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorDefaultsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorList) DeepCopyInto(out *OperatorList) {
 	*out = *in
@@ -85,9 +265,222 @@ func (in *OperatorList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSet) DeepCopyInto(out *OperatorSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSet.
+func (in *OperatorSet) DeepCopy() *OperatorSet {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetList) DeepCopyInto(out *OperatorSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OperatorSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetList.
+func (in *OperatorSetList) DeepCopy() *OperatorSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperatorSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetMemberStatus) DeepCopyInto(out *OperatorSetMemberStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetMemberStatus.
+func (in *OperatorSetMemberStatus) DeepCopy() *OperatorSetMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetPackage) DeepCopyInto(out *OperatorSetPackage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetPackage.
+func (in *OperatorSetPackage) DeepCopy() *OperatorSetPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetSpec) DeepCopyInto(out *OperatorSetSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]OperatorSetPackage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetSpec.
+func (in *OperatorSetSpec) DeepCopy() *OperatorSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetStatus) DeepCopyInto(out *OperatorSetStatus) {
+	*out = *in
+	if in.Operators != nil {
+		in, out := &in.Operators, &out.Operators
+		*out = make([]OperatorSetMemberStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetStatus.
+func (in *OperatorSetStatus) DeepCopy() *OperatorSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorSetTemplate) DeepCopyInto(out *OperatorSetTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(CatalogReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSetTemplate.
+func (in *OperatorSetTemplate) DeepCopy() *OperatorSetTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorSetTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorSpec) DeepCopyInto(out *OperatorSpec) {
 	*out = *in
+	if in.VersionPreference != nil {
+		in, out := &in.VersionPreference, &out.VersionPreference
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VersionExcludes != nil {
+		in, out := &in.VersionExcludes, &out.VersionExcludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Channels != nil {
+		in, out := &in.Channels, &out.Channels
+		*out = make([]ChannelName, len(*in))
+		copy(*out, *in)
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = new(CatalogReference)
+		**out = **in
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BundleOverride != nil {
+		in, out := &in.BundleOverride, &out.BundleOverride
+		*out = new(BundleOverride)
+		**out = **in
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]LifecycleHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSpec.
@@ -103,6 +496,48 @@ func (in *OperatorSpec) DeepCopy() *OperatorSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
 	*out = *in
+	if in.InstalledBundleProvenance != nil {
+		in, out := &in.InstalledBundleProvenance, &out.InstalledBundleProvenance
+		*out = new(BundleProvenance)
+		**out = **in
+	}
+	if in.ProvidedAPIs != nil {
+		in, out := &in.ProvidedAPIs, &out.ProvidedAPIs
+		*out = make([]v1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradePath != nil {
+		in, out := &in.UpgradePath, &out.UpgradePath
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SkippedVersions != nil {
+		in, out := &in.SkippedVersions, &out.SkippedVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultsConflicts != nil {
+		in, out := &in.DefaultsConflicts, &out.DefaultsConflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResolutionHistory != nil {
+		in, out := &in.ResolutionHistory, &out.ResolutionHistory
+		*out = make([]ResolutionDecision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LifecycleHooks != nil {
+		in, out := &in.LifecycleHooks, &out.LifecycleHooks
+		*out = make([]LifecycleHookStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.UpgradeGraph != nil {
+		in, out := &in.UpgradeGraph, &out.UpgradeGraph
+		*out = new(UpgradeGraph)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -121,3 +556,140 @@ func (in *OperatorStatus) DeepCopy() *OperatorStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedAPI) DeepCopyInto(out *ProvidedAPI) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedAPI.
+func (in *ProvidedAPI) DeepCopy() *ProvidedAPI {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedAPI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProvidedAPI) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedAPIList) DeepCopyInto(out *ProvidedAPIList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ProvidedAPI, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedAPIList.
+func (in *ProvidedAPIList) DeepCopy() *ProvidedAPIList {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedAPIList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ProvidedAPIList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedAPISpec) DeepCopyInto(out *ProvidedAPISpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedAPISpec.
+func (in *ProvidedAPISpec) DeepCopy() *ProvidedAPISpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedAPISpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedAPIStatus) DeepCopyInto(out *ProvidedAPIStatus) {
+	*out = *in
+	if in.Providers != nil {
+		in, out := &in.Providers, &out.Providers
+		*out = make([]APIProvider, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedAPIStatus.
+func (in *ProvidedAPIStatus) DeepCopy() *ProvidedAPIStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedAPIStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolutionDecision) DeepCopyInto(out *ResolutionDecision) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolutionDecision.
+func (in *ResolutionDecision) DeepCopy() *ResolutionDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolutionDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeGraph) DeepCopyInto(out *UpgradeGraph) {
+	*out = *in
+	if in.Successors != nil {
+		in, out := &in.Successors, &out.Successors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Heads != nil {
+		in, out := &in.Heads, &out.Heads
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeGraph.
+func (in *UpgradeGraph) DeepCopy() *UpgradeGraph {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeGraph)
+	in.DeepCopyInto(out)
+	return out
+}
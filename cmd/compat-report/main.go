@@ -0,0 +1,108 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// compat-report prints whether the channel heads of a file-based catalog can
+// be installed by operator-controller.
+//
+//	compat-report [-o text|json] [-fail] <catalog directory>
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/operator-framework/operator-controller/pkg/compat"
+)
+
+func main() {
+	var output string
+	var fail bool
+	flag.StringVar(&output, "o", "text", "The output format, text or json.")
+	flag.BoolVar(&fail, "fail", false, "Exit with status 2 if any channel head is not installable.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <catalog directory>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || (output != "text" && output != "json") {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	catalog, err := compat.Load(os.DirFS(flag.Arg(0)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	report := catalog.Report()
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeText(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if fail && !installable(report) {
+		os.Exit(2)
+	}
+}
+
+func writeText(w io.Writer, report compat.Report) error {
+	for _, pkg := range report.Packages {
+		if _, err := fmt.Fprintf(w, "%s\n", pkg.Name); err != nil {
+			return err
+		}
+		for _, head := range pkg.Heads {
+			status := "installable"
+			if !head.Installable {
+				status = "not installable: " + strings.Join(head.Problems, "; ")
+			}
+			if _, err := fmt.Fprintf(w, "  %s (%s) [%s]: %s\n", head.Name, head.Version, strings.Join(head.Channels, ", "), status); err != nil {
+				return err
+			}
+			if head.NeedsWebhooks {
+				if _, err := fmt.Fprintf(w, "    needs webhook support\n"); err != nil {
+					return err
+				}
+			}
+			if len(head.Dependencies) > 0 {
+				if _, err := fmt.Fprintf(w, "    depends on %s\n", strings.Join(head.Dependencies, ", ")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func installable(report compat.Report) bool {
+	for _, pkg := range report.Packages {
+		for _, head := range pkg.Heads {
+			if !head.Installable {
+				return false
+			}
+		}
+	}
+	return true
+}
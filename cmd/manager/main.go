@@ -17,14 +17,22 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy/solver"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
-	"k8s.io/apimachinery/pkg/runtime"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -33,13 +41,27 @@ import (
 
 	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/config"
 	"github.com/operator-framework/operator-controller/internal/controllers"
+	"github.com/operator-framework/operator-controller/internal/features"
+	"github.com/operator-framework/operator-controller/internal/kubeversion"
+	"github.com/operator-framework/operator-controller/internal/profiling"
 	"github.com/operator-framework/operator-controller/internal/resolution/entitysources"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/olm"
 )
 
+// memoryLimitPercent is the share of the container's memory limit given to
+// the Go runtime as its soft memory limit. The rest is headroom for memory the
+// runtime doesn't account for and for the time the GC needs to react, so that
+// the container isn't OOM-killed before the GC has freed memory.
+const memoryLimitPercent = 90
+
+// kubeVersionTTL is how long the API server's version is used before it is
+// read again.
+const kubeVersionTTL = 5 * time.Minute
+
 var (
-	scheme   = runtime.NewScheme()
+	scheme   = apiruntime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
 )
 
@@ -57,20 +79,81 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var configFile string
+	var pprofAddr string
+	var kubeAPIQPS float64
+	var kubeAPIBurst int
+	base := config.Default()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&pprofAddr, "pprof-bind-address", "0",
+		"The address the pprof endpoints bind to, e.g. localhost:6060. Set to 0 to disable profiling.")
+	flag.Float64Var(&kubeAPIQPS, "kube-api-qps", 20, "The maximum queries per second to the Kubernetes API server.")
+	flag.IntVar(&kubeAPIBurst, "kube-api-burst", 30, "The maximum burst of queries to the Kubernetes API server.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&configFile, "config", "",
+		"Path to a configuration file. Values set in the file take precedence over the equivalent flags, "+
+			"and reloadable values are picked up without a restart when the file changes.")
+	flag.IntVar(&base.MaxConcurrentReconciles, "max-concurrent-reconciles", base.MaxConcurrentReconciles,
+		"The maximum number of Operators reconciled in parallel.")
+	flag.StringVar(&base.FieldManager, "field-manager", base.FieldManager,
+		"The field manager name used when applying BundleDeployments with server-side apply.")
+	flag.StringVar((*string)(&base.ApplyConflictPolicy), "apply-conflict-policy", string(base.ApplyConflictPolicy),
+		"How server-side apply conflicts with other field managers are handled. "+
+			"One of: Force (take ownership of conflicting fields), Fail (report the conflict and leave the fields untouched).")
+	flag.DurationVar(&base.Timeouts.Resolution.Duration, "resolution-timeout", base.Timeouts.Resolution.Duration,
+		"The maximum time spent resolving a bundle for an Operator in a single reconcile. Zero disables the timeout.")
+	flag.DurationVar(&base.Timeouts.Apply.Duration, "apply-timeout", base.Timeouts.Apply.Duration,
+		"The maximum time spent applying an Operator's BundleDeployment in a single reconcile. Zero disables the timeout.")
+	flag.StringVar(&base.ClusterUpgradeTarget, "cluster-upgrade-target", base.ClusterUpgradeTarget,
+		"The Kubernetes version the cluster is being upgraded to. Bundles that only support it are reported as available after the upgrade.")
+	flag.DurationVar(&base.StartupJitter.Duration, "startup-jitter", base.StartupJitter.Duration,
+		"The window over which the first reconcile of each Operator after startup is spread. Zero disables the jitter.")
+	flag.StringVar(&base.LifecycleHooks.Namespace, "lifecycle-hook-namespace", base.LifecycleHooks.Namespace,
+		"The namespace lifecycle hook Jobs are created in. Lifecycle hooks fail without running while it is empty.")
+	flag.StringVar(&base.LifecycleHooks.ServiceAccountName, "lifecycle-hook-service-account", base.LifecycleHooks.ServiceAccountName,
+		"The service account lifecycle hook pods run as. Defaults to the default service account of the lifecycle hook namespace.")
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
-	flag.Parse()
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	features.OperatorControllerFeatureGate.AddFlag(pflag.CommandLine)
+	pflag.Parse()
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts), zap.StacktraceLevel(zapcore.DPanicLevel)))
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
+	if err := base.Validate(); err != nil {
+		setupLog.Error(err, "invalid flag value")
+		os.Exit(1)
+	}
+	cfg := base
+	if configFile != "" {
+		var err error
+		if cfg, err = config.Load(configFile, base); err != nil {
+			setupLog.Error(err, "unable to load config file")
+			os.Exit(1)
+		}
+	}
+	// Gates set in the config file take precedence over the flag.
+	if err := features.OperatorControllerFeatureGate.SetFromMap(cfg.FeatureGates); err != nil {
+		setupLog.Error(err, "unable to set feature gates from config file")
+		os.Exit(1)
+	}
+	features.RecordMetrics()
+	cfgStore := config.NewStore(cfg)
+
+	// GOMAXPROCS and the memory limit are set from the container's resource
+	// limits in the default deployment.
+	setMemoryLimitFromContainer()
+	setupLog.Info("runtime settings", "GOMAXPROCS", runtime.GOMAXPROCS(0), "GOMEMLIMIT", debug.SetMemoryLimit(-1))
+
+	restConfig := ctrl.GetConfigOrDie()
+	restConfig.QPS = float32(kubeAPIQPS)
+	restConfig.Burst = kubeAPIBurst
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
 		Port:                   9443,
@@ -94,19 +177,77 @@ func main() {
 		os.Exit(1)
 	}
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	// The version is read again once it is older than kubeVersionTTL, so that
+	// an in-place control plane upgrade is picked up without a restart.
+	kubeVersionSource := kubeversion.NewSource(discoveryClient, kubeVersionTTL)
+	kubeVersion, err := kubeVersionSource.KubeVersion(context.Background())
+	if err != nil {
+		setupLog.Error(err, "unable to determine the cluster's Kubernetes version")
+		os.Exit(1)
+	}
+	var upgradeTarget *semver.Version
+	if cfg.ClusterUpgradeTarget != "" {
+		// The target was validated with the rest of the configuration.
+		target, _ := semver.ParseTolerant(cfg.ClusterUpgradeTarget)
+		target = kubeversion.Normalize(target)
+		upgradeTarget = &target
+	}
+	setupLog.Info("resolving bundles for kubernetes version", "version", kubeVersion.String(), "upgradeTarget", cfg.ClusterUpgradeTarget)
+
 	if err = (&controllers.OperatorReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 		Resolver: solver.NewDeppySolver(
-			entitysources.NewCatalogdEntitySource(mgr.GetClient()),
-			olm.NewOLMVariableSource(mgr.GetClient()),
+			entitysources.NewCatalogdEntitySource(mgr.GetClient()).WithEventRecorder(mgr.GetEventRecorderFor("operator-controller")),
+			olm.NewOLMVariableSource(mgr.GetClient(), olm.WithKubeVersionFunc(kubeVersionSource.KubeVersion, upgradeTarget)),
 		),
+		Config:      cfgStore,
+		APIReader:   mgr.GetAPIReader(),
+		KubeVersion: kubeVersionSource.KubeVersion,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Operator")
 		os.Exit(1)
 	}
+	if features.OperatorControllerFeatureGate.Enabled(features.OperatorSets) {
+		if err = (&controllers.OperatorSetReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+			Config: cfgStore,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OperatorSet")
+			os.Exit(1)
+		}
+	}
+	if features.OperatorControllerFeatureGate.Enabled(features.ProvidedAPIIndex) {
+		if err = (&controllers.ProvidedAPIReconciler{
+			Client: mgr.GetClient(),
+			Scheme: mgr.GetScheme(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ProvidedAPI")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
+	if configFile != "" {
+		if err := mgr.Add(config.NewWatcher(configFile, base, cfgStore, ctrl.Log.WithName("config"))); err != nil {
+			setupLog.Error(err, "unable to set up config file watcher")
+			os.Exit(1)
+		}
+	}
+
+	if pprofAddr != "0" && pprofAddr != "" {
+		if err := mgr.Add(profiling.NewServer(pprofAddr, ctrl.Log.WithName("pprof"))); err != nil {
+			setupLog.Error(err, "unable to set up pprof server")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -122,3 +263,17 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// setMemoryLimitFromContainer sets the Go runtime's soft memory limit to
+// memoryLimitPercent of the container memory limit in CONTAINER_MEMORY_LIMIT,
+// in bytes. An explicit GOMEMLIMIT takes precedence.
+func setMemoryLimitFromContainer() {
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); ok {
+		return
+	}
+	limit, err := strconv.ParseInt(os.Getenv("CONTAINER_MEMORY_LIMIT"), 10, 64)
+	if err != nil || limit <= 0 {
+		return
+	}
+	debug.SetMemoryLimit(limit / 100 * memoryLimitPercent)
+}
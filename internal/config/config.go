@@ -0,0 +1,322 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-controller/internal/features"
+)
+
+// DefaultFieldManager is the server-side apply field manager used for
+// BundleDeployments when none is configured.
+const DefaultFieldManager = "operator-controller"
+
+// ApplyConflictPolicy determines how server-side apply conflicts with other
+// field managers are handled when applying BundleDeployments.
+type ApplyConflictPolicy string
+
+const (
+	// ApplyConflictPolicyForce takes ownership of conflicting fields.
+	ApplyConflictPolicyForce ApplyConflictPolicy = "Force"
+	// ApplyConflictPolicyFail leaves conflicting fields to their current
+	// owner and reports the conflict as an installation failure.
+	ApplyConflictPolicyFail ApplyConflictPolicy = "Fail"
+)
+
+// Config is the operator-controller configuration. It is read from the file
+// passed with --config, which is typically mounted from a ConfigMap.
+//
+// Fields documented as reloadable take effect on the next reconcile after the
+// file changes. All other fields are only read at startup.
+type Config struct {
+	// MaxConcurrentReconciles is the maximum number of Operators reconciled
+	// in parallel. Only read at startup.
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+
+	// FieldManager is the field manager used when applying BundleDeployments.
+	// Reloadable.
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	// ApplyConflictPolicy controls how apply conflicts with other field
+	// managers are handled. One of Force or Fail. Reloadable.
+	ApplyConflictPolicy ApplyConflictPolicy `json:"applyConflictPolicy,omitempty"`
+
+	// PackagePolicy restricts the packages Operators may install. Reloadable;
+	// Operators are checked against the new policy the next time they are
+	// reconciled.
+	PackagePolicy PackagePolicy `json:"packagePolicy,omitempty"`
+
+	// Timeouts bounds the time a reconcile may spend in each phase, so that a
+	// hung catalog read or a blocked apply cannot stall a worker indefinitely.
+	// Reloadable.
+	Timeouts Timeouts `json:"timeouts,omitempty"`
+
+	// ClusterUpgradeTarget is the Kubernetes version the cluster is being
+	// upgraded to, e.g. "1.28". Bundles that need a newer version than the
+	// cluster's are never resolved, but while this is set the ones the target
+	// supports are reported as available after the cluster upgrade. Only read
+	// at startup.
+	ClusterUpgradeTarget string `json:"clusterUpgradeTarget,omitempty"`
+
+	// StartupJitter spreads the first reconcile of each Operator after the
+	// controller starts over this window, so that a restart doesn't resolve
+	// and apply every Operator at once. Zero disables the jitter. Only read
+	// at startup.
+	StartupJitter metav1.Duration `json:"startupJitter,omitempty"`
+
+	// RequeueRateLimit bounds the overall rate at which Operators are
+	// requeued after errors. Only read at startup.
+	RequeueRateLimit RateLimit `json:"requeueRateLimit,omitempty"`
+
+	// LifecycleHooks is where the Jobs of Operators' lifecycle hooks run.
+	// Reloadable.
+	LifecycleHooks LifecycleHooks `json:"lifecycleHooks,omitempty"`
+
+	// FeatureGates enables or disables feature gates by name, on top of the
+	// --feature-gates flag. Some gates decide which controllers run, so this is
+	// only read at startup.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// LifecycleHooks confines lifecycle hook Jobs to a namespace and service
+// account chosen by the cluster admin, so that Operator authors can't use the
+// controller to run pods with privileges they don't have themselves.
+type LifecycleHooks struct {
+	// Namespace is the namespace hook Jobs are created in. Hooks fail without
+	// running while it is empty.
+	Namespace string `json:"namespace,omitempty"`
+
+	// ServiceAccountName is the service account hook pods run as. Defaults to
+	// the default service account of Namespace.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+func (h LifecycleHooks) validate() error {
+	if h.Namespace != "" {
+		if errs := validation.IsDNS1123Label(h.Namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid lifecycleHooks.namespace %q: %s", h.Namespace, strings.Join(errs, ", "))
+		}
+	}
+	if h.ServiceAccountName != "" {
+		if errs := validation.IsDNS1123Subdomain(h.ServiceAccountName); len(errs) > 0 {
+			return fmt.Errorf("invalid lifecycleHooks.serviceAccountName %q: %s", h.ServiceAccountName, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// RateLimit is a token bucket.
+type RateLimit struct {
+	// QPS is the rate at which tokens are added to the bucket.
+	QPS float64 `json:"qps,omitempty"`
+
+	// Burst is the size of the bucket.
+	Burst int `json:"burst,omitempty"`
+}
+
+func (r RateLimit) validate() error {
+	if r.QPS <= 0 {
+		return fmt.Errorf("invalid requeueRateLimit.qps %v: must be positive", r.QPS)
+	}
+	if r.Burst < 1 {
+		return fmt.Errorf("invalid requeueRateLimit.burst %d: must be at least 1", r.Burst)
+	}
+	return nil
+}
+
+// Timeouts holds the per-phase reconcile timeouts. A zero value disables the
+// timeout for that phase.
+type Timeouts struct {
+	// Resolution bounds running the resolver against the catalog content.
+	Resolution metav1.Duration `json:"resolution,omitempty"`
+
+	// Apply bounds applying the BundleDeployment.
+	Apply metav1.Duration `json:"apply,omitempty"`
+}
+
+func (t Timeouts) validate() error {
+	if t.Resolution.Duration < 0 {
+		return fmt.Errorf("invalid timeouts.resolution %s: must not be negative", t.Resolution.Duration)
+	}
+	if t.Apply.Duration < 0 {
+		return fmt.Errorf("invalid timeouts.apply %s: must not be negative", t.Apply.Duration)
+	}
+	return nil
+}
+
+// PackagePolicy lists the package names Operators are allowed or denied to
+// install. Entries are path.Match patterns, e.g. "prometheus" or "acme-*".
+type PackagePolicy struct {
+	// Allow, if not empty, is the set of packages that may be installed.
+	// Packages that match no entry are denied.
+	Allow []string `json:"allow,omitempty"`
+
+	// Deny is the set of packages that may not be installed. It takes
+	// precedence over Allow.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// Check returns an error describing the violation if packageName is not
+// allowed by the policy.
+func (p PackagePolicy) Check(packageName string) error {
+	for _, pattern := range p.Deny {
+		if ok, _ := path.Match(pattern, packageName); ok {
+			return fmt.Errorf("package %q is denied by pattern %q", packageName, pattern)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, pattern := range p.Allow {
+		if ok, _ := path.Match(pattern, packageName); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("package %q is not in the list of allowed packages", packageName)
+}
+
+func (p PackagePolicy) validate() error {
+	for _, pattern := range append(append([]string{}, p.Allow...), p.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid packagePolicy pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Default returns the configuration used when no configuration file is given.
+func Default() Config {
+	return Config{
+		MaxConcurrentReconciles: 1,
+		FieldManager:            DefaultFieldManager,
+		ApplyConflictPolicy:     ApplyConflictPolicyForce,
+		Timeouts: Timeouts{
+			Resolution: metav1.Duration{Duration: 2 * time.Minute},
+			Apply:      metav1.Duration{Duration: time.Minute},
+		},
+		// The same bucket controller-runtime uses by default.
+		RequeueRateLimit: RateLimit{QPS: 10, Burst: 100},
+	}
+}
+
+// Validate returns an error if the configuration contains invalid values.
+func (c Config) Validate() error {
+	if c.MaxConcurrentReconciles < 1 {
+		return fmt.Errorf("invalid maxConcurrentReconciles %d: must be at least 1", c.MaxConcurrentReconciles)
+	}
+	if c.FieldManager == "" {
+		return fmt.Errorf("invalid fieldManager: must not be empty")
+	}
+	switch c.ApplyConflictPolicy {
+	case ApplyConflictPolicyForce, ApplyConflictPolicyFail:
+	default:
+		return fmt.Errorf("invalid applyConflictPolicy %q: must be one of %q, %q", c.ApplyConflictPolicy, ApplyConflictPolicyForce, ApplyConflictPolicyFail)
+	}
+	if err := c.Timeouts.validate(); err != nil {
+		return err
+	}
+	if c.StartupJitter.Duration < 0 {
+		return fmt.Errorf("invalid startupJitter %s: must not be negative", c.StartupJitter.Duration)
+	}
+	if err := c.RequeueRateLimit.validate(); err != nil {
+		return err
+	}
+	if err := c.LifecycleHooks.validate(); err != nil {
+		return err
+	}
+	if len(c.FeatureGates) > 0 {
+		// Check the names against a copy, so that validating a file never
+		// changes the gates in use.
+		if err := features.OperatorControllerFeatureGate.DeepCopy().SetFromMap(c.FeatureGates); err != nil {
+			return fmt.Errorf("invalid featureGates: %w", err)
+		}
+	}
+	if c.ClusterUpgradeTarget != "" {
+		if _, err := semver.ParseTolerant(c.ClusterUpgradeTarget); err != nil {
+			return fmt.Errorf("invalid clusterUpgradeTarget %q: %w", c.ClusterUpgradeTarget, err)
+		}
+	}
+	return c.PackagePolicy.validate()
+}
+
+// RequiresRestart returns true if c and other differ in fields that are only
+// read at startup.
+func (c Config) RequiresRestart(other Config) bool {
+	return c.MaxConcurrentReconciles != other.MaxConcurrentReconciles ||
+		c.ClusterUpgradeTarget != other.ClusterUpgradeTarget ||
+		c.StartupJitter != other.StartupJitter ||
+		c.RequeueRateLimit != other.RequeueRateLimit ||
+		!reflect.DeepEqual(c.FeatureGates, other.FeatureGates)
+}
+
+// Load reads the configuration file at path on top of base. Fields that are
+// not set in the file keep their value from base. Unknown fields are rejected.
+func Load(path string, base Config) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading config file: %w", err)
+	}
+	cfg := base
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing config file %q: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("validating config file %q: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Store holds the current configuration and can be safely read while it is
+// being updated by a Watcher.
+type Store struct {
+	mu  sync.RWMutex
+	cfg Config
+}
+
+// NewStore returns a Store holding cfg.
+func NewStore(cfg Config) *Store {
+	return &Store{cfg: cfg}
+}
+
+// Get returns the current configuration. A nil Store returns Default().
+func (s *Store) Get() Config {
+	if s == nil {
+		return Default()
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Set replaces the current configuration.
+func (s *Store) Set(cfg Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
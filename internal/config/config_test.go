@@ -0,0 +1,212 @@
+package config_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/operator-framework/operator-controller/internal/config"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		dir  string
+		path string
+	)
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, "config.yaml")
+	})
+
+	writeConfig := func(content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	Describe("Load", func() {
+		It("keeps values from the base config that are not set in the file", func() {
+			writeConfig("applyConflictPolicy: Fail\n")
+			base := config.Default()
+			base.FieldManager = "from-flags"
+
+			cfg, err := config.Load(path, base)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.ApplyConflictPolicy).To(Equal(config.ApplyConflictPolicyFail))
+			Expect(cfg.FieldManager).To(Equal("from-flags"))
+			Expect(cfg.MaxConcurrentReconciles).To(Equal(1))
+		})
+		It("rejects unknown fields", func() {
+			writeConfig("notAField: true\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring(`unknown field "notAField"`)))
+		})
+		It("rejects invalid values", func() {
+			writeConfig("applyConflictPolicy: Sometimes\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring(`invalid applyConflictPolicy "Sometimes"`)))
+		})
+		It("parses timeouts as durations", func() {
+			writeConfig("timeouts:\n  resolution: 30s\n")
+			cfg, err := config.Load(path, config.Default())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Timeouts.Resolution.Duration).To(Equal(30 * time.Second))
+			Expect(cfg.Timeouts.Apply.Duration).To(Equal(config.Default().Timeouts.Apply.Duration))
+		})
+		It("rejects negative timeouts", func() {
+			writeConfig("timeouts:\n  apply: -1s\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring("invalid timeouts.apply -1s")))
+		})
+		It("rejects cluster upgrade targets that are not versions", func() {
+			writeConfig("clusterUpgradeTarget: next\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring(`invalid clusterUpgradeTarget "next"`)))
+		})
+		It("keeps the default requeue burst when only the qps is set", func() {
+			writeConfig("startupJitter: 30s\nrequeueRateLimit:\n  qps: 2\n")
+			cfg, err := config.Load(path, config.Default())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.StartupJitter.Duration).To(Equal(30 * time.Second))
+			Expect(cfg.RequeueRateLimit).To(Equal(config.RateLimit{QPS: 2, Burst: 100}))
+		})
+		It("rejects requeue rate limits that are not positive", func() {
+			writeConfig("requeueRateLimit:\n  qps: -1\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring("invalid requeueRateLimit.qps -1")))
+		})
+		It("reads feature gates", func() {
+			writeConfig("featureGates:\n  LifecycleHooks: true\n")
+			cfg, err := config.Load(path, config.Default())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.FeatureGates).To(Equal(map[string]bool{"LifecycleHooks": true}))
+		})
+		It("rejects unknown feature gates", func() {
+			writeConfig("featureGates:\n  NotAGate: true\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring("invalid featureGates: unrecognized feature gate: NotAGate")))
+		})
+		It("rejects lifecycle hook namespaces that are not valid names", func() {
+			writeConfig("lifecycleHooks:\n  namespace: Hooks\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring(`invalid lifecycleHooks.namespace "Hooks"`)))
+		})
+		It("keeps the flag values of fields with a flag when loading the shipped ConfigMap", func() {
+			data, err := os.ReadFile(filepath.Join("..", "..", "config", "manager", "controller_config.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			cm := &corev1.ConfigMap{}
+			Expect(yaml.Unmarshal(data, cm)).To(Succeed())
+			writeConfig(cm.Data["config.yaml"])
+
+			base := config.Default()
+			base.MaxConcurrentReconciles = 4
+			base.FieldManager = "flag-manager"
+			base.ApplyConflictPolicy = config.ApplyConflictPolicyFail
+			base.Timeouts.Resolution.Duration = 30 * time.Second
+			base.Timeouts.Apply.Duration = 0
+			cfg, err := config.Load(path, base)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.MaxConcurrentReconciles).To(Equal(4))
+			Expect(cfg.FieldManager).To(Equal("flag-manager"))
+			Expect(cfg.ApplyConflictPolicy).To(Equal(config.ApplyConflictPolicyFail))
+			Expect(cfg.Timeouts).To(Equal(base.Timeouts))
+		})
+		It("returns an error if the file does not exist", func() {
+			_, err := config.Load(filepath.Join(dir, "missing.yaml"), config.Default())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("PackagePolicy", func() {
+		It("allows every package when empty", func() {
+			Expect(config.PackagePolicy{}.Check("prometheus")).To(Succeed())
+		})
+		It("denies packages matching a deny pattern", func() {
+			p := config.PackagePolicy{Deny: []string{"acme-*"}}
+			Expect(p.Check("acme-operator")).To(MatchError(`package "acme-operator" is denied by pattern "acme-*"`))
+			Expect(p.Check("prometheus")).To(Succeed())
+		})
+		It("only allows packages matching an allow pattern", func() {
+			p := config.PackagePolicy{Allow: []string{"prometheus", "cert-*"}}
+			Expect(p.Check("prometheus")).To(Succeed())
+			Expect(p.Check("cert-manager")).To(Succeed())
+			Expect(p.Check("argocd")).To(MatchError(`package "argocd" is not in the list of allowed packages`))
+		})
+		It("gives deny precedence over allow", func() {
+			p := config.PackagePolicy{Allow: []string{"*"}, Deny: []string{"argocd"}}
+			Expect(p.Check("argocd")).To(HaveOccurred())
+		})
+		It("rejects malformed patterns", func() {
+			writeConfig("packagePolicy:\n  deny: [\"[\"]\n")
+			_, err := config.Load(path, config.Default())
+			Expect(err).To(MatchError(ContainSubstring(`invalid packagePolicy pattern "["`)))
+		})
+	})
+
+	Describe("Store", func() {
+		It("returns the default config when nil", func() {
+			var s *config.Store
+			Expect(s.Get()).To(Equal(config.Default()))
+		})
+	})
+
+	Describe("Watcher", func() {
+		var (
+			store  *config.Store
+			cancel context.CancelFunc
+			done   chan struct{}
+		)
+		BeforeEach(func() {
+			writeConfig("fieldManager: first\n")
+			cfg, err := config.Load(path, config.Default())
+			Expect(err).NotTo(HaveOccurred())
+			store = config.NewStore(cfg)
+
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			w := config.NewWatcher(path, config.Default(), store, logr.Discard())
+			go func() {
+				defer GinkgoRecover()
+				defer close(done)
+				Expect(w.Start(ctx)).To(Succeed())
+			}()
+		})
+		AfterEach(func() {
+			cancel()
+			Eventually(done).Should(BeClosed())
+		})
+
+		It("reloads reloadable values when the file changes", func() {
+			Eventually(func() string {
+				writeConfig("fieldManager: second\n")
+				return store.Get().FieldManager
+			}).Should(Equal("second"))
+		})
+		It("keeps startup-only values when the file changes", func() {
+			Eventually(func() string {
+				writeConfig("fieldManager: second\nmaxConcurrentReconciles: 5\n")
+				return store.Get().FieldManager
+			}).Should(Equal("second"))
+			Expect(store.Get().MaxConcurrentReconciles).To(Equal(1))
+		})
+		It("keeps the feature gates when the file changes", func() {
+			Eventually(func() string {
+				writeConfig("fieldManager: second\nfeatureGates:\n  LifecycleHooks: true\n")
+				return store.Get().FieldManager
+			}).Should(Equal("second"))
+			Expect(store.Get().FeatureGates).To(BeEmpty())
+		})
+		It("keeps the current config when the file becomes invalid", func() {
+			writeConfig("applyConflictPolicy: Sometimes\n")
+			Consistently(func() string {
+				return store.Get().FieldManager
+			}, "200ms").Should(Equal("first"))
+		})
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+)
+
+// Watcher reloads a configuration file into a Store whenever it changes.
+// It implements manager.Runnable and manager.LeaderElectionRunnable.
+type Watcher struct {
+	path  string
+	base  Config
+	store *Store
+	log   logr.Logger
+}
+
+// NewWatcher returns a Watcher that reloads the file at path on top of base
+// into store.
+func NewWatcher(path string, base Config, store *Store, log logr.Logger) *Watcher {
+	return &Watcher{
+		path:  path,
+		base:  base,
+		store: store,
+		log:   log,
+	}
+}
+
+// NeedLeaderElection returns false so that every replica keeps its
+// configuration up to date, not just the leader.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the configuration file until ctx is done.
+func (w *Watcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// Watch the parent directory rather than the file itself: ConfigMap
+	// volumes update files by atomically swapping a symlinked directory,
+	// which a watch on the file would not survive.
+	if err := watcher.Add(filepath.Dir(w.path)); err != nil {
+		return err
+	}
+	w.log.Info("watching config file", "path", w.path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			w.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.log.Error(err, "config file watch error")
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	current := w.store.Get()
+	cfg, err := Load(w.path, w.base)
+	if err != nil {
+		w.log.Error(err, "keeping current config")
+		return
+	}
+	if reflect.DeepEqual(cfg, current) {
+		return
+	}
+	if current.RequiresRestart(cfg) {
+		w.log.Info("config change to startup-only fields will take effect after restart")
+		cfg.MaxConcurrentReconciles = current.MaxConcurrentReconciles
+		cfg.ClusterUpgradeTarget = current.ClusterUpgradeTarget
+		cfg.StartupJitter = current.StartupJitter
+		cfg.RequeueRateLimit = current.RequeueRateLimit
+		cfg.FeatureGates = current.FeatureGates
+	}
+	w.store.Set(cfg)
+	w.log.Info("reloaded config file", "path", w.path)
+}
@@ -117,4 +117,110 @@ var _ = Describe("Operator Spec Validations", func() {
 		Expect(err).To(HaveOccurred(), "expected error for invalid channel length")
 		Expect(err.Error()).To(ContainSubstring("spec.channel: Too long: may not be longer than 48"))
 	})
+	It("should fail if both channel and channels are given", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Channel:     "stable",
+			Channels:    []operatorsv1alpha1.ChannelName{"fast"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for channel and channels")
+		Expect(err.Error()).To(ContainSubstring("channel and channels cannot both be set"))
+	})
+	It("should fail if an invalid channels entry is given", func() {
+		for _, invalidChannel := range []operatorsv1alpha1.ChannelName{"", "Capitalized", "end-with-period."} {
+			err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+				PackageName: "package",
+				Channels:    []operatorsv1alpha1.ChannelName{"stable", invalidChannel},
+			}))
+			Expect(err).To(HaveOccurred(), "expected error for invalid channels entry %q", invalidChannel)
+			Expect(err.Error()).To(ContainSubstring("spec.channels[1] in body should match '^[a-z0-9]+([\\.-][a-z0-9]+)*$'"))
+		}
+	})
+	It("should fail if a channels entry is longer than 48 characters", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Channels:    []operatorsv1alpha1.ChannelName{"longname01234567890123456789012345678901234567890"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for long channels entry")
+		Expect(err.Error()).To(ContainSubstring("spec.channels[0]: Too long: may not be longer than 48"))
+	})
+	It("should fail if a channel is listed more than once in channels", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Channels:    []operatorsv1alpha1.ChannelName{"stable", "fast", "stable"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for duplicate channels")
+		Expect(err.Error()).To(ContainSubstring(`spec.channels[2]: Duplicate value: "stable"`))
+	})
+	It("should pass if valid channels are given", func() {
+		op := operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Channels:    []operatorsv1alpha1.ChannelName{"stable", "channel-has-version-1.0.1"},
+		})
+		Expect(cl.Create(ctx, op)).To(Succeed())
+		Expect(cl.Delete(ctx, op)).To(Succeed())
+	})
+	It("should fail if both version and versionPreference are given", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName:       "package",
+			Version:           "1.2.3",
+			VersionPreference: []string{"2.x"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for version and versionPreference")
+		Expect(err.Error()).To(ContainSubstring("version and versionPreference cannot both be set"))
+	})
+	It("should fail if a versionPreference entry is longer than 64 characters", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName:       "package",
+			VersionPreference: []string{">=1.0.0 <1.0.1 || >=1.1.0 <1.1.1 || >=1.2.0 <1.2.1 || >=1.3.0 <1.3.1"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for long versionPreference entry")
+		Expect(err.Error()).To(ContainSubstring("versionPreference entries may not be longer than 64 characters"))
+	})
+	It("should pass if only versionPreference is given", func() {
+		op := operator(operatorsv1alpha1.OperatorSpec{
+			PackageName:       "package",
+			VersionPreference: []string{"2.x", "1.9.x"},
+		})
+		Expect(cl.Create(ctx, op)).To(Succeed())
+		Expect(cl.Delete(ctx, op)).To(Succeed())
+	})
+	It("should fail if the operator depends on itself", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			DependsOn:   []string{"other-operator", "test-operator"},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for self dependency")
+		Expect(err.Error()).To(ContainSubstring("spec.dependsOn must not contain the operator itself"))
+	})
+	It("should pass if the operator depends on other operators", func() {
+		op := operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			DependsOn:   []string{"other-operator"},
+		})
+		Expect(cl.Create(ctx, op)).To(Succeed())
+		Expect(cl.Delete(ctx, op)).To(Succeed())
+	})
+	It("should fail if the catalog ref is not a digest reference", func() {
+		err := cl.Create(ctx, operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Catalog: &operatorsv1alpha1.CatalogReference{
+				Name: "operatorhub",
+				Ref:  "quay.io/operatorhubio/catalog:latest",
+			},
+		}))
+		Expect(err).To(HaveOccurred(), "expected error for tag catalog ref")
+		Expect(err.Error()).To(ContainSubstring("catalog ref must be a digest reference"))
+	})
+	It("should pass if the catalog ref is a digest reference", func() {
+		op := operator(operatorsv1alpha1.OperatorSpec{
+			PackageName: "package",
+			Catalog: &operatorsv1alpha1.CatalogReference{
+				Name: "operatorhub",
+				Ref:  "quay.io/operatorhubio/catalog@sha256:0123456789abcdef",
+			},
+		})
+		Expect(cl.Create(ctx, op)).To(Succeed())
+		Expect(cl.Delete(ctx, op)).To(Succeed())
+	})
 })
@@ -0,0 +1,74 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResolutionError is returned by Reconcile when no bundle could be resolved
+// for an Operator. Reconcile reports it with the Resolved condition set to
+// False with reason ResolutionFailed. Use errors.As on the wrapped error to
+// inspect the cause, e.g. a required_package.PackageNotFoundError.
+type ResolutionError struct {
+	Err error
+}
+
+func (e *ResolutionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyError is returned by Reconcile when an object could not be applied to
+// the cluster. Reconcile reports it with the Installed condition set to False
+// with reason InstallationFailed.
+type ApplyError struct {
+	// Object is the object that failed to apply.
+	Object client.Object
+	Err    error
+}
+
+func (e *ApplyError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is wrapped in the error returned by Reconcile when a reconcile
+// phase did not finish within its configured timeout.
+type TimeoutError struct {
+	// Phase is the reconcile phase that timed out, e.g. "resolution".
+	Phase   string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s: %v", e.Phase, e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
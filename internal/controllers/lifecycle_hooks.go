@@ -0,0 +1,229 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/config"
+)
+
+// LifecycleHookLabel is set on lifecycle hook Jobs to the name of the hook.
+const LifecycleHookLabel = "operators.operatorframework.io/lifecycle-hook"
+
+// OperatorLabel is set on lifecycle hook Jobs to the name of their Operator.
+const OperatorLabel = "operators.operatorframework.io/operator"
+
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+
+// runLifecycleHooks runs the Operator's hooks of the given phase for bundle and
+// records their state in status. It returns the names of the hooks that are
+// still running and of the hooks that failed with the Fail policy. Hooks
+// that already finished for bundle are not run again.
+func (r *OperatorReconciler) runLifecycleHooks(ctx context.Context, op *operatorsv1alpha1.Operator, phase operatorsv1alpha1.LifecycleHookPhase, bundle string) (running, failed []string, err error) {
+	pruneLifecycleHookStatuses(op)
+	for _, hook := range op.Spec.LifecycleHooks {
+		if hook.Phase != phase {
+			continue
+		}
+		status := lifecycleHookStatus(op, hook.Name)
+		cfg := r.Config.Get().LifecycleHooks
+		if err := checkLifecycleHook(cfg, hook); err != nil {
+			*status = operatorsv1alpha1.LifecycleHookStatus{
+				Name:    hook.Name,
+				Bundle:  bundle,
+				State:   operatorsv1alpha1.LifecycleHookFailed,
+				Message: err.Error(),
+			}
+		} else if status.Bundle != bundle || status.Job == "" || status.State == operatorsv1alpha1.LifecycleHookRunning {
+			if err := r.observeLifecycleHookJob(ctx, op, hook, cfg, bundle, status); err != nil {
+				return nil, nil, err
+			}
+		}
+		switch status.State {
+		case operatorsv1alpha1.LifecycleHookRunning:
+			running = append(running, hook.Name)
+		case operatorsv1alpha1.LifecycleHookFailed:
+			if hook.FailurePolicy != operatorsv1alpha1.LifecycleHookFailurePolicyIgnore {
+				failed = append(failed, fmt.Sprintf("%s (%s)", hook.Name, status.Message))
+			}
+		}
+	}
+	return running, failed, nil
+}
+
+// pendingBundleResource returns the bundle resource desired installs, or ""
+// if the existing BundleDeployment already has it.
+func (r *OperatorReconciler) pendingBundleResource(ctx context.Context, desired *unstructured.Unstructured) (string, error) {
+	desiredTyped := &rukpakv1alpha1.BundleDeployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(desired.UnstructuredContent(), desiredTyped); err != nil {
+		return "", err
+	}
+	resource := bundleSourceResource(desiredTyped.Spec.Template.Spec.Source)
+	existing := &rukpakv1alpha1.BundleDeployment{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: desired.GetName()}, existing)
+	if apierrors.IsNotFound(err) {
+		return resource, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if bundleSourceResource(existing.Spec.Template.Spec.Source) == resource {
+		return "", nil
+	}
+	return resource, nil
+}
+
+// checkLifecycleHook returns an error if hook can't run with cfg. Hooks only
+// run in the namespace and as the service account the cluster admin
+// configured, so that the controller's permission to create Jobs can't be
+// used to run pods with other privileges.
+func checkLifecycleHook(cfg config.LifecycleHooks, hook operatorsv1alpha1.LifecycleHook) error {
+	if cfg.Namespace == "" {
+		return fmt.Errorf("no namespace is configured for lifecycle hooks")
+	}
+	serviceAccount := cfg.ServiceAccountName
+	if serviceAccount == "" {
+		serviceAccount = "default"
+	}
+	podSpec := hook.Job.Template.Spec
+	for _, name := range []string{podSpec.ServiceAccountName, podSpec.DeprecatedServiceAccount} {
+		if name != "" && name != serviceAccount {
+			return fmt.Errorf("service account %q is not allowed: lifecycle hooks run as %q", name, serviceAccount)
+		}
+	}
+	return nil
+}
+
+// observeLifecycleHookJob creates the hook's Job for bundle if it doesn't
+// exist and updates status from it.
+func (r *OperatorReconciler) observeLifecycleHookJob(ctx context.Context, op *operatorsv1alpha1.Operator, hook operatorsv1alpha1.LifecycleHook, cfg config.LifecycleHooks, bundle string, status *operatorsv1alpha1.LifecycleHookStatus) error {
+	job := &batchv1.Job{}
+	name := lifecycleHookJobName(op.GetName(), hook.Name, bundle)
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: cfg.Namespace, Name: name}, job)
+	if apierrors.IsNotFound(err) {
+		job = generateLifecycleHookJob(op, hook, cfg, name)
+		err = r.Client.Create(ctx, job)
+	}
+	if err != nil {
+		return err
+	}
+
+	*status = operatorsv1alpha1.LifecycleHookStatus{
+		Name:   hook.Name,
+		Bundle: bundle,
+		Job:    name,
+		State:  operatorsv1alpha1.LifecycleHookRunning,
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.State = operatorsv1alpha1.LifecycleHookSucceeded
+		case batchv1.JobFailed:
+			status.State = operatorsv1alpha1.LifecycleHookFailed
+			status.Message = cond.Message
+		}
+	}
+	return nil
+}
+
+func generateLifecycleHookJob(op *operatorsv1alpha1.Operator, hook operatorsv1alpha1.LifecycleHook, cfg config.LifecycleHooks, name string) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cfg.Namespace,
+			Labels: map[string]string{
+				OperatorLabel:      op.GetName(),
+				LifecycleHookLabel: hook.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+					Kind:               "Operator",
+					Name:               op.GetName(),
+					UID:                op.GetUID(),
+					Controller:         pointer.Bool(true),
+					BlockOwnerDeletion: pointer.Bool(true),
+				},
+			},
+		},
+		Spec: *hook.Job.DeepCopy(),
+	}
+	job.Spec.Template.Spec.ServiceAccountName = cfg.ServiceAccountName
+	job.Spec.Template.Spec.DeprecatedServiceAccount = ""
+	if hook.TimeoutSeconds != nil {
+		job.Spec.ActiveDeadlineSeconds = pointer.Int64(*hook.TimeoutSeconds)
+	}
+	return job
+}
+
+// lifecycleHookJobName returns a Job name that is unique to the Operator, the
+// hook and the bundle and fits in a label value.
+func lifecycleHookJobName(opName, hookName, bundle string) string {
+	sum := sha256.Sum256([]byte(opName + "/" + hookName + "/" + bundle))
+	prefix := fmt.Sprintf("%s-%s", opName, hookName)
+	if len(prefix) > 52 {
+		prefix = strings.TrimRight(prefix[:52], "-.")
+	}
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(sum[:])[:10])
+}
+
+// lifecycleHookStatus returns the status entry for the named hook, adding an
+// empty one if there is none.
+func lifecycleHookStatus(op *operatorsv1alpha1.Operator, name string) *operatorsv1alpha1.LifecycleHookStatus {
+	for i := range op.Status.LifecycleHooks {
+		if op.Status.LifecycleHooks[i].Name == name {
+			return &op.Status.LifecycleHooks[i]
+		}
+	}
+	op.Status.LifecycleHooks = append(op.Status.LifecycleHooks, operatorsv1alpha1.LifecycleHookStatus{Name: name})
+	return &op.Status.LifecycleHooks[len(op.Status.LifecycleHooks)-1]
+}
+
+// pruneLifecycleHookStatuses removes the status of hooks that are no longer
+// in the Operator's spec.
+func pruneLifecycleHookStatuses(op *operatorsv1alpha1.Operator) {
+	hooks := map[string]bool{}
+	for _, hook := range op.Spec.LifecycleHooks {
+		hooks[hook.Name] = true
+	}
+	var statuses []operatorsv1alpha1.LifecycleHookStatus
+	for _, status := range op.Status.LifecycleHooks {
+		if hooks[status.Name] {
+			statuses = append(statuses, status)
+		}
+	}
+	op.Status.LifecycleHooks = statuses
+}
@@ -18,68 +18,146 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/blang/semver/v4"
 	"github.com/go-logr/logr"
 	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
 	"github.com/operator-framework/deppy/pkg/deppy/solver"
+	"github.com/operator-framework/operator-registry/alpha/property"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	"golang.org/x/time/rate"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/config"
 	"github.com/operator-framework/operator-controller/internal/controllers/validators"
+	"github.com/operator-framework/operator-controller/internal/defaults"
+	"github.com/operator-framework/operator-controller/internal/features"
+	"github.com/operator-framework/operator-controller/internal/resolution/constraints"
+	"github.com/operator-framework/operator-controller/internal/resolution/upgradepath"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/required_package"
 )
 
+// AppliedAtAnnotation is set on BundleDeployments to the time, in RFC 3339
+// format, at which their desired state was last applied.
+const AppliedAtAnnotation = "operators.operatorframework.io/applied-at"
+
 // OperatorReconciler reconciles a Operator object
 type OperatorReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Resolver *solver.DeppySolver
+
+	// Config provides the reloadable controller configuration. If nil,
+	// config.Default() is used.
+	Config *config.Store
+
+	// APIReader reads instances of the APIs provided by an Operator when its
+	// deletion is checked for dependents, without starting informers for
+	// them. If nil, the Client is used.
+	APIReader client.Reader
+
+	// KubeVersion, if set, returns the cluster's Kubernetes version that the
+	// Resolver resolves bundles for. A cached resolution is not reused once it
+	// changes.
+	KubeVersion func(context.Context) (semver.Version, error)
+
+	resolutionCache resolutionCache
+	startupJitter   *startupJitter
+
+	// evaluator is shared by reconciles so that compiled constraint rules are reused.
+	evaluatorOnce sync.Once
+	evaluator     *constraints.Evaluator
+	evaluatorErr  error
 }
 
-//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators,verbs=get;list;watch
+// deletionRecheckInterval is how often the deletion of an Operator blocked by
+// dependents is checked again. Instances of the provided APIs are not watched.
+const deletionRecheckInterval = 30 * time.Second
+
+// deletionProtectionAggregationLabel labels the ClusterRoles that grant list
+// access to the APIs provided by Operators with the Block deletion policy. They
+// are aggregated into the deletion protection ClusterRole bound to the manager,
+// which has no access to them otherwise.
+const deletionProtectionAggregationLabel = "operators.operatorframework.io/aggregate-to-deletion-protection"
+
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=providedapis,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operatordefaults,verbs=get;list;watch
 
 //+kubebuilder:rbac:groups=core.rukpak.io,resources=bundledeployments,verbs=get;list;watch;create;update;patch
 
-//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=bundlemetadata,verbs=list;watch
-//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=packages,verbs=list;watch
-//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=catalogs,verbs=list;watch
+//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=bundlemetadata,verbs=get;list;watch
+//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=packages,verbs=get;list;watch
+//+kubebuilder:rbac:groups=catalogd.operatorframework.io,resources=catalogs,verbs=get;list;watch
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 
 func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	l := log.FromContext(ctx).WithName("operator-controller")
 	l.V(1).Info("starting")
 	defer l.V(1).Info("ending")
 
+	if delay := r.startupJitter.delay(req.Name, time.Now()); delay > 0 {
+		l.V(1).Info("delaying first reconcile after startup", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	var existingOp = &operatorsv1alpha1.Operator{}
 	if err := r.Get(ctx, req.NamespacedName, existingOp); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
 	reconciledOp := existingOp.DeepCopy()
-	res, reconcileErr := r.reconcile(ctx, reconciledOp)
+	res, reconcileErr := r.reconcileWithDefaults(ctx, reconciledOp)
+	reconciledOp.Status.Phase = operatorPhase(reconciledOp)
+	if reconciledOp.Status.InstalledBundleResource == "" {
+		reconciledOp.Status.InstalledBundleProvenance = nil
+	}
+	if reconciledOp.Status.ResolvedBundleResource == "" {
+		reconciledOp.Status.ResolvedChannel = ""
+		reconciledOp.Status.ProvidedAPIs = nil
+	}
 
 	// Do checks before any Update()s, as Update() may modify the resource structure!
 	updateStatus := !equality.Semantic.DeepEqual(existingOp.Status, reconciledOp.Status)
 	updateFinalizers := !equality.Semantic.DeepEqual(existingOp.Finalizers, reconciledOp.Finalizers)
 	unexpectedFieldsChanged := checkForUnexpectedFieldChange(*existingOp, *reconciledOp)
 
+	finalizers := reconciledOp.GetFinalizers()
 	if updateStatus {
 		if updateErr := r.Status().Update(ctx, reconciledOp); updateErr != nil {
 			return res, utilerrors.NewAggregate([]error{reconcileErr, updateErr})
@@ -91,6 +169,8 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	if updateFinalizers {
+		// The status update replaced the object with the server's copy.
+		reconciledOp.SetFinalizers(finalizers)
 		if updateErr := r.Update(ctx, reconciledOp); updateErr != nil {
 			return res, utilerrors.NewAggregate([]error{reconcileErr, updateErr})
 		}
@@ -99,6 +179,30 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	return res, reconcileErr
 }
 
+// reconcileWithDefaults reconciles op with the OperatorDefaults that select it
+// applied to its spec. Only the status and finalizers are kept, as defaults
+// are never written to the Operator. Fields that several of them set to
+// different values are reported in status.defaultsConflicts.
+func (r *OperatorReconciler) reconcileWithDefaults(ctx context.Context, op *operatorsv1alpha1.Operator) (ctrl.Result, error) {
+	operatorDefaults, err := defaults.List(ctx, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	conflicts, err := defaults.Conflicts(op, operatorDefaults)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	effectiveOp := op.DeepCopy()
+	if err := defaults.Apply(effectiveOp, operatorDefaults); err != nil {
+		return ctrl.Result{}, err
+	}
+	res, reconcileErr := r.reconcile(ctx, effectiveOp)
+	op.Status = effectiveOp.Status
+	op.Status.DefaultsConflicts = conflicts
+	op.SetFinalizers(effectiveOp.GetFinalizers())
+	return res, reconcileErr
+}
+
 // Compare resources - ignoring status & metadata.finalizers
 func checkForUnexpectedFieldChange(a, b operatorsv1alpha1.Operator) bool {
 	a.Status, b.Status = operatorsv1alpha1.OperatorStatus{}, operatorsv1alpha1.OperatorStatus{}
@@ -108,10 +212,31 @@ func checkForUnexpectedFieldChange(a, b operatorsv1alpha1.Operator) bool {
 
 // Helper function to do the actual reconcile
 func (r *OperatorReconciler) reconcile(ctx context.Context, op *operatorsv1alpha1.Operator) (ctrl.Result, error) {
+	if !op.GetDeletionTimestamp().IsZero() {
+		return r.reconcileDeletion(ctx, op)
+	}
+	if op.Spec.DeletionPolicy == operatorsv1alpha1.DeletionPolicyBlock {
+		controllerutil.AddFinalizer(op, operatorsv1alpha1.DeletionProtectionFinalizer)
+	} else {
+		controllerutil.RemoveFinalizer(op, operatorsv1alpha1.DeletionProtectionFinalizer)
+	}
+
+	// A new value of the reconcile annotation requests a repair: nothing
+	// cached or already applied is trusted for this reconcile.
+	repair := false
+	if requested := op.GetAnnotations()[operatorsv1alpha1.ReconcileRequestAnnotation]; requested != "" && requested != op.Status.LastHandledReconcileRequest {
+		log.FromContext(ctx).Info("repair requested", "request", requested)
+		r.resolutionCache.invalidate()
+		op.Status.LastHandledReconcileRequest = requested
+		repair = true
+	}
+
 	// validate spec
 	if err := validators.ValidateOperatorSpec(op); err != nil {
 		// Set the TypeInstalled condition to Unknown to indicate that the resolution
 		// hasn't been attempted yet, due to the spec being invalid.
+		op.Status.UpgradeGraph = nil
+		op.Status.UpgradePath = nil
 		op.Status.InstalledBundleResource = ""
 		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as spec is invalid", op.GetGeneration())
 		// Set the TypeResolved condition to Unknown to indicate that the resolution
@@ -120,77 +245,1180 @@ func (r *OperatorReconciler) reconcile(ctx context.Context, op *operatorsv1alpha
 		setResolvedStatusConditionUnknown(&op.Status.Conditions, "validation has not been attempted as spec is invalid", op.GetGeneration())
 		return ctrl.Result{}, nil
 	}
+	if err := r.Config.Get().PackagePolicy.Check(op.Spec.PackageName); err != nil {
+		op.Status.UpgradeGraph = nil
+		op.Status.UpgradePath = nil
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
+		op.Status.ResolvedBundleResource = ""
+		setResolvedStatusConditionPolicyViolation(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
+	// Resolving against a catalog that is missing or still unpacking would
+	// report its packages as not found.
+	if msg, err := r.checkCatalogAvailable(ctx, op); err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	} else if msg != "" {
+		op.Status.UpgradeGraph = nil
+		op.Status.UpgradePath = nil
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
+		op.Status.ResolvedBundleResource = ""
+		// The Catalog watch requeues the Operator once the catalog becomes available.
+		setResolvedStatusConditionCatalogUnavailable(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
+	// A pinned catalog snapshot must be the content currently served by the
+	// Catalog, otherwise resolution would silently use different content.
+	if msg, err := r.checkCatalogSnapshot(ctx, op); err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	} else if msg != "" {
+		op.Status.UpgradeGraph = nil
+		op.Status.UpgradePath = nil
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
+		op.Status.ResolvedBundleResource = ""
+		// The Catalog watch requeues the Operator once the snapshot becomes available.
+		setResolvedStatusConditionCatalogSnapshotUnavailable(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
 	// run resolution
-	solution, err := r.Resolver.Solve(ctx)
+	solution, err := r.solve(ctx)
 	if err != nil {
+		// The package may be missing only because the catalog that serves it
+		// has not been unpacked yet.
+		var notFoundErr *required_package.PackageNotFoundError
+		if !errors.As(err, &notFoundErr) {
+			return ctrl.Result{}, setResolutionFailed(op, err)
+		}
+		unavailable, listErr := r.unavailableCatalogs(ctx)
+		if listErr != nil {
+			return ctrl.Result{}, setResolutionFailed(op, err)
+		}
+		if len(unavailable) == 0 {
+			// Otherwise a catalog update removed what the Operator was installed
+			// from, e.g. its package or channel. The solve is global, so the
+			// missing package may also be another Operator's, which is not a
+			// reason to keep this one's bundle.
+			if notFoundErr.PackageName == op.Spec.PackageName {
+				if kept, keepErr := r.keepLastKnownGoodResolution(ctx, op, err); keepErr != nil || kept {
+					return ctrl.Result{}, keepErr
+				}
+			}
+			return ctrl.Result{}, setResolutionFailed(op, err)
+		}
+		op.Status.UpgradeGraph = nil
+		op.Status.UpgradePath = nil
 		op.Status.InstalledBundleResource = ""
 		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
 		op.Status.ResolvedBundleResource = ""
-		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		setResolvedStatusConditionCatalogUnavailable(&op.Status.Conditions,
+			fmt.Sprintf("%v; %s", err, strings.Join(unavailable, "; ")), op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
+	// lookup the bundle entity in the solution that corresponds to the
+	// Operator's desired package name.
+	bundleEntity, err := r.getBundleEntityFromSolution(solution, op.Spec.PackageName)
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+
+	// Get the bundle image reference for the bundle
+	bundleImage, err := bundleEntity.BundlePath()
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+
+	resolvedChannel, err := bundleEntity.ChannelName()
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+
+	bundleMetadata, err := r.resolvedBundleMetadata(ctx, bundleEntity)
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+	apis, err := providedAPIs(bundleEntity, bundleMetadata)
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+	provenance, err := bundleProvenance(bundleEntity, bundleImage)
+	if err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	}
+	// What keeps the resolved bundle from being installed is only known once
+	// the Installed condition has been set below.
+	defer recordResolutionDecision(op, provenance, metav1.Now())
+
+	// Now we can set the Resolved Condition, and the resolvedBundleSource field to the bundleImage value.
+	op.Status.ResolvedBundleResource = bundleImage
+	op.Status.ResolvedChannel = resolvedChannel
+	op.Status.ProvidedAPIs = apis
+	resolvedMsg := fmt.Sprintf("resolved to %q", bundleImage)
+	if afterUpgrade, err := availableAfterClusterUpgrade(solution, bundleEntity); err != nil {
+		return ctrl.Result{}, setResolutionFailed(op, err)
+	} else if len(afterUpgrade) > 0 {
+		resolvedMsg += fmt.Sprintf("; newer bundles available after cluster upgrade: %s", strings.Join(afterUpgrade, ", "))
+	}
+	setResolvedStatusConditionSuccess(&op.Status.Conditions, resolvedMsg, op.GetGeneration())
+
+	upgradeGraph, err := r.upgradeGraphForBundle(ctx, bundleEntity)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	op.Status.UpgradeGraph = upgradeGraph
+
+	mediaType, err := bundleEntity.MediaType()
+	if err != nil {
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+	bundleProvisioner, err := mapBundleMediaTypeToBundleProvisioner(mediaType)
+	if err != nil {
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+	// Don't install or upgrade until every dependency reports Installed. The
+	// existing BundleDeployment, if any, is left untouched in the meantime.
+	if msg, err := r.checkDependencies(ctx, op); err != nil {
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	} else if msg != "" {
+		// Changes to the dependencies requeue this Operator.
+		setInstalledStatusConditionWaitingForDependencies(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
+	// Capabilities the bundle requires from already installed Operators block
+	// installation the same way dependencies do.
+	if msg, err := r.checkConstraints(ctx, op, bundleMetadata); err != nil {
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	} else if msg != "" {
+		setInstalledStatusConditionWaitingForDependencies(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+	// Don't install a bundle whose APIs are already provided by another
+	// Operator. The conflict is retried with backoff until it is resolved.
+	if msg, err := r.checkAPIConflicts(ctx, op, bundleMetadata); err != nil {
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
 		return ctrl.Result{}, err
+	} else if msg != "" {
+		setInstalledStatusConditionFailed(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, errors.New(msg)
+	}
+
+	// Don't take over a BundleDeployment that was not created for this
+	// Operator unless its adoption policy allows it.
+	if msg, err := r.bundleDeploymentOwnershipConflict(ctx, op); err != nil {
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	} else if msg != "" {
+		setInstalledStatusConditionOwnershipConflict(&op.Status.Conditions, msg, op.GetGeneration())
+		return ctrl.Result{}, nil
+	}
+
+	// Ensure a BundleDeployment exists with its bundle source from the bundle
+	// image we just looked up in the solution.
+	// With the Sequential upgrade strategy, the bundles between the current
+	// and the resolved bundle are installed first, one at a time.
+	installImage, upgradePath := bundleImage, []string(nil)
+	if op.Spec.BundleOverride == nil && (op.Spec.UpgradeStrategy == operatorsv1alpha1.UpgradeStrategySequential || op.Spec.UpgradeConstraintPolicy != "") {
+		upgrade, err := r.pendingChannelUpgrade(ctx, op, bundleEntity, bundleImage)
+		if err != nil {
+			setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return ctrl.Result{}, err
+		}
+		// Upgrades that skip versions of the channel must be acknowledged
+		// with the AllowSkip policy, and the skipped versions are recorded.
+		if upgrade != nil && op.Spec.UpgradeConstraintPolicy != "" {
+			skipped, err := skippedVersions(op, upgrade)
+			if err != nil {
+				return ctrl.Result{}, setUpgradeFailed(op, err)
+			}
+			if len(skipped) > 0 && op.Spec.UpgradeConstraintPolicy != operatorsv1alpha1.UpgradeConstraintPolicyAllowSkip {
+				setInstalledStatusConditionSkipNotAcknowledged(&op.Status.Conditions, fmt.Sprintf("upgrade to %q skips versions %s; set spec.upgradeConstraintPolicy to %s to allow it",
+					upgrade.bundleName, strings.Join(skipped, ", "), operatorsv1alpha1.UpgradeConstraintPolicyAllowSkip), op.GetGeneration())
+				return ctrl.Result{}, nil
+			}
+			op.Status.SkippedVersions = skipped
+		}
+		installImage, upgradePath, err = nextUpgradeHop(op, upgrade, bundleImage)
+		if err != nil {
+			return ctrl.Result{}, setUpgradeFailed(op, err)
+		}
+	}
+	op.Status.UpgradePath = upgradePath
+	dep := r.generateExpectedBundleDeployment(*op, installImage, bundleProvisioner)
+	if override := op.Spec.BundleOverride; override != nil {
+		if err := setBundleDeploymentSource(dep, override); err != nil {
+			setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return ctrl.Result{}, err
+		}
+	}
+	// PreInstall hooks run before a bundle the BundleDeployment doesn't
+	// have yet is applied. Changes to their Jobs requeue this Operator.
+	desiredBundle, err := r.pendingBundleResource(ctx, dep)
+	if err != nil {
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+	if desiredBundle != "" {
+		running, failed, err := r.runLifecycleHooks(ctx, op, operatorsv1alpha1.LifecycleHookPreInstall, desiredBundle)
+		if err != nil {
+			setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return ctrl.Result{}, err
+		}
+		if len(failed) > 0 {
+			setInstalledStatusConditionHookFailed(&op.Status.Conditions, fmt.Sprintf("lifecycle hooks failed: %s", strings.Join(failed, ", ")), op.GetGeneration())
+			return ctrl.Result{}, nil
+		}
+		if len(running) > 0 {
+			setInstalledStatusConditionWaitingForHooks(&op.Status.Conditions, fmt.Sprintf("waiting for lifecycle hooks: %s", strings.Join(running, ", ")), op.GetGeneration())
+			return ctrl.Result{}, nil
+		}
+	}
+	if err := withPhaseTimeout(ctx, "apply", r.Config.Get().Timeouts.Apply.Duration, func(ctx context.Context) error {
+		return r.ensureBundleDeployment(ctx, dep, repair)
+	}); err != nil {
+		// originally Reason: operatorsv1alpha1.ReasonInstallationFailed
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+
+	// convert existing unstructured object into bundleDeployment for easier mapping of status.
+	existingTypedBundleDeployment := &rukpakv1alpha1.BundleDeployment{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(dep.UnstructuredContent(), existingTypedBundleDeployment); err != nil {
+		// originally Reason: operatorsv1alpha1.ReasonInstallationStatusUnknown
+		op.Status.InstalledBundleResource = ""
+		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return ctrl.Result{}, err
+	}
+
+	// Let's set the proper Installed condition and InstalledBundleResource field based on the
+	// existing BundleDeployment object status.
+	mapBDStatusToInstalledCondition(existingTypedBundleDeployment, op)
+	if override := op.Spec.BundleOverride; override != nil {
+		setInstalledStatusConditionOverridden(&op.Status.Conditions, override, bundleImage, op.GetGeneration())
+	}
+	// PostInstall hooks run once the bundle is installed. The Operator is not
+	// reported as Installed until they have finished.
+	if installed := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); installed.Status == metav1.ConditionTrue &&
+		op.Status.InstalledBundleResource == bundleSourceResource(existingTypedBundleDeployment.Spec.Template.Spec.Source) {
+		running, failed, err := r.runLifecycleHooks(ctx, op, operatorsv1alpha1.LifecycleHookPostInstall, op.Status.InstalledBundleResource)
+		if err != nil {
+			setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
+			return ctrl.Result{}, err
+		}
+		if len(failed) > 0 {
+			setInstalledStatusConditionHookFailed(&op.Status.Conditions, fmt.Sprintf("lifecycle hooks failed: %s", strings.Join(failed, ", ")), op.GetGeneration())
+		} else if len(running) > 0 {
+			setInstalledStatusConditionWaitingForHooks(&op.Status.Conditions, fmt.Sprintf("waiting for lifecycle hooks: %s", strings.Join(running, ", ")), op.GetGeneration())
+		}
+	}
+	op.Status.InstalledBundleProvenance = nil
+	if op.Status.InstalledBundleResource == bundleImage {
+		provenance, err := bundleProvenance(bundleEntity, bundleImage)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		op.Status.InstalledBundleProvenance = provenance
+	}
+
+	// set the status of the operator based on the respective bundle deployment status conditions.
+	return checkProgressDeadline(existingTypedBundleDeployment, op, time.Now()), nil
+}
+
+// keepLastKnownGoodResolution keeps an installed Operator on the bundle it was
+// last resolved to when its package or channel is no longer found in updated
+// catalog content, rather than failing an install that is still healthy. It reports
+// whether the resolution was kept. The BundleDeployment is left untouched and
+// the Catalog watch requeues the Operator when the catalog content changes again.
+func (r *OperatorReconciler) keepLastKnownGoodResolution(ctx context.Context, op *operatorsv1alpha1.Operator, resolveErr error) (bool, error) {
+	// Only resolution that regressed without a spec change is kept: a spec
+	// that can't be resolved is reported as a resolution failure.
+	current := func(c *metav1.Condition) bool {
+		return c != nil && c.ObservedGeneration == op.GetGeneration()
+	}
+	installed := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+	resolved := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeResolved)
+	provenance := op.Status.InstalledBundleProvenance
+	if !current(installed) || installed.Status != metav1.ConditionTrue ||
+		!current(resolved) || (resolved.Status != metav1.ConditionTrue && resolved.Reason != operatorsv1alpha1.ReasonResolutionRegressed) ||
+		provenance == nil || provenance.Image != op.Status.InstalledBundleResource {
+		return false, nil
+	}
+
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: op.GetName()}, bd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !metav1.IsControlledBy(bd, op) || bundleSourceResource(bd.Spec.Template.Spec.Source) != provenance.Image {
+		return false, nil
+	}
+
+	mapBDStatusToInstalledCondition(bd, op)
+	if op.Status.InstalledBundleResource != provenance.Image {
+		op.Status.InstalledBundleProvenance = nil
+	}
+	op.Status.ResolvedBundleResource = provenance.Image
+	message := fmt.Sprintf("%v; keeping installed bundle %q", resolveErr, provenance.Image)
+	if provenance.CatalogRef != "" {
+		message += fmt.Sprintf(" resolved from catalog ref %q", provenance.CatalogRef)
+	}
+	setResolvedStatusConditionRegressed(&op.Status.Conditions, message, op.GetGeneration())
+	return true, nil
+}
+
+// bundleProvenance returns the provenance of the bundle installed from bundleImage.
+func bundleProvenance(bundle *entity.BundleEntity, bundleImage string) (*operatorsv1alpha1.BundleProvenance, error) {
+	catalog, err := bundle.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	provenance := &operatorsv1alpha1.BundleProvenance{
+		Image:      bundleImage,
+		Catalog:    catalog.Name,
+		CatalogRef: catalog.Ref,
+	}
+	if i := strings.LastIndex(bundleImage, "@"); i >= 0 {
+		provenance.Digest = bundleImage[i+1:]
+	}
+	return provenance, nil
+}
+
+// resolutionHistoryLimit is the number of entries kept in status.resolutionHistory.
+const resolutionHistoryLimit = 10
+
+// recordResolutionDecision adds the resolved bundle to the Operator's resolution
+// history, with the reason of the Installed condition, unless it is installed.
+// A decision that only differs from the latest entry in its message updates
+// that entry instead.
+func recordResolutionDecision(op *operatorsv1alpha1.Operator, resolved *operatorsv1alpha1.BundleProvenance, now metav1.Time) {
+	installed := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+	if installed == nil || installed.Status == metav1.ConditionTrue && op.Status.InstalledBundleResource == resolved.Image {
+		return
+	}
+	decision := operatorsv1alpha1.ResolutionDecision{
+		Time:       now,
+		Bundle:     resolved.Image,
+		Catalog:    resolved.Catalog,
+		CatalogRef: resolved.CatalogRef,
+		Reason:     installed.Reason,
+		Message:    installed.Message,
+	}
+	history := op.Status.ResolutionHistory
+	if n := len(history); n > 0 {
+		last := &history[n-1]
+		if last.Bundle == decision.Bundle && last.Catalog == decision.Catalog && last.CatalogRef == decision.CatalogRef && last.Reason == decision.Reason {
+			last.Message = decision.Message
+			return
+		}
+	}
+	history = append(history, decision)
+	if len(history) > resolutionHistoryLimit {
+		history = history[len(history)-resolutionHistoryLimit:]
+	}
+	op.Status.ResolutionHistory = history
+}
+
+// checkProgressDeadline sets the Installed condition to False with reason
+// ProgressDeadlineExceeded if the BundleDeployment has not been installed within
+// the Operator's progress deadline of being applied. While the deadline has not
+// passed, it returns a result that requeues the Operator when it does.
+func checkProgressDeadline(bd *rukpakv1alpha1.BundleDeployment, op *operatorsv1alpha1.Operator, now time.Time) ctrl.Result {
+	if op.Spec.ProgressDeadlineSeconds == nil || apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) {
+		return ctrl.Result{}
+	}
+	appliedAt, err := time.Parse(time.RFC3339, bd.GetAnnotations()[AppliedAtAnnotation])
+	if err != nil {
+		return ctrl.Result{}
+	}
+	deadline := time.Duration(*op.Spec.ProgressDeadlineSeconds) * time.Second
+	if remaining := appliedAt.Add(deadline).Sub(now); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}
+	}
+	msg := fmt.Sprintf("bundledeployment was not installed within %s of being applied", deadline)
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); cond != nil && cond.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, cond.Message)
+	}
+	setInstalledStatusConditionProgressDeadlineExceeded(&op.Status.Conditions, msg, op.GetGeneration())
+	return ctrl.Result{}
+}
+
+// solve runs the resolver, reusing the previous solution if neither the
+// Operators' resolution inputs nor the catalogs have changed since.
+func (r *OperatorReconciler) solve(ctx context.Context) (*solver.Solution, error) {
+	operators := &operatorsv1alpha1.OperatorList{}
+	if err := r.Client.List(ctx, operators); err != nil {
+		return nil, err
+	}
+	if err := defaults.ApplyAll(ctx, r.Client, operators.Items); err != nil {
+		return nil, err
+	}
+	catalogContent, err := catalogContentVersions(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	installed, err := installedBundleKeys(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+	var kubeVersion string
+	if r.KubeVersion != nil {
+		v, err := r.KubeVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		kubeVersion = v.String()
+	}
+	key, err := resolutionInputsKey(operators.Items, installed, catalogContent, kubeVersion)
+	if err != nil {
+		return nil, err
+	}
+	solution, generation := r.resolutionCache.get(key)
+	if solution != nil {
+		log.FromContext(ctx).V(1).Info("reusing cached resolution")
+		return solution, nil
+	}
+
+	err = withPhaseTimeout(ctx, "resolution", r.Config.Get().Timeouts.Resolution.Duration, func(ctx context.Context) error {
+		var err error
+		solution, err = r.Resolver.Solve(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.resolutionCache.set(key, generation, solution)
+	return solution, nil
+}
+
+// withPhaseTimeout runs fn with a context that is cancelled after timeout. If
+// the timeout expires before fn returns, the error is wrapped in a
+// TimeoutError naming the phase. A zero timeout runs fn with ctx unchanged.
+func withPhaseTimeout(ctx context.Context, phase string, timeout time.Duration, fn func(context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fn(phaseCtx)
+	if err != nil && errors.Is(phaseCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return &TimeoutError{Phase: phase, Timeout: timeout, Err: err}
+	}
+	return err
+}
+
+// reconcileDeletion removes the DeletionProtectionFinalizer from a deleted
+// Operator once nothing depends on it.
+func (r *OperatorReconciler) reconcileDeletion(ctx context.Context, op *operatorsv1alpha1.Operator) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(op, operatorsv1alpha1.DeletionProtectionFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	if op.Spec.DeletionPolicy == operatorsv1alpha1.DeletionPolicyBlock {
+		msg, err := r.deletionBlockers(ctx, op)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if msg != "" {
+			setInstalledStatusConditionDeletionBlocked(&op.Status.Conditions, msg, op.GetGeneration())
+			// Deletion bumps the generation but doesn't change the resolution.
+			if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeResolved); cond != nil {
+				cond.ObservedGeneration = op.GetGeneration()
+			}
+			return ctrl.Result{RequeueAfter: deletionRecheckInterval}, nil
+		}
+	}
+	controllerutil.RemoveFinalizer(op, operatorsv1alpha1.DeletionProtectionFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// deletionBlockers returns a non-empty message listing the Operators that
+// depend on op and the APIs provided by op that still have instances.
+func (r *OperatorReconciler) deletionBlockers(ctx context.Context, op *operatorsv1alpha1.Operator) (string, error) {
+	var blockers []string
+	operators := &operatorsv1alpha1.OperatorList{}
+	if err := r.Client.List(ctx, operators); err != nil {
+		return "", err
+	}
+	for _, other := range operators.Items {
+		if other.GetName() == op.GetName() || !other.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		for _, dep := range other.Spec.DependsOn {
+			if dep == op.GetName() {
+				blockers = append(blockers, fmt.Sprintf("operator %q depends on it", other.GetName()))
+			}
+		}
+	}
+
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	apis := &operatorsv1alpha1.ProvidedAPIList{}
+	// Without the index, the APIs provided by the Operator are not known.
+	if features.OperatorControllerFeatureGate.Enabled(features.ProvidedAPIIndex) {
+		if err := r.Client.List(ctx, apis); err != nil {
+			return "", err
+		}
+	}
+	for _, api := range apis.Items {
+		for _, p := range api.Status.Providers {
+			if p.Operator != op.GetName() || len(p.Versions) == 0 {
+				continue
+			}
+			instances := &metav1.PartialObjectMetadataList{}
+			instances.SetGroupVersionKind(schema.GroupVersionKind{Group: api.Spec.Group, Version: p.Versions[0], Kind: api.Spec.Kind + "List"})
+			if err := reader.List(ctx, instances, client.Limit(1)); err != nil {
+				if apimeta.IsNoMatchError(err) {
+					continue
+				}
+				// Deletion stays blocked until the instances can be checked.
+				if apierrors.IsForbidden(err) {
+					blockers = append(blockers, fmt.Sprintf("instances of %s can't be listed without a ClusterRole labeled %s=true", api.GetName(), deletionProtectionAggregationLabel))
+					continue
+				}
+				return "", err
+			}
+			if len(instances.Items) > 0 {
+				blockers = append(blockers, fmt.Sprintf("instances of %s exist", api.GetName()))
+			}
+		}
+	}
+	if len(blockers) > 0 {
+		return fmt.Sprintf("deletion is blocked: %s", strings.Join(blockers, ", ")), nil
+	}
+	return "", nil
+}
+
+// setUpgradeFailed records a failure to plan the upgrade to the resolved
+// bundle in the Operator's status. A channel without an upgrade path from the
+// installed bundle is reported with reason NoUpgradePath and is not retried
+// with backoff, as the Catalog watch requeues the Operator when it changes;
+// any other error is returned.
+func setUpgradeFailed(op *operatorsv1alpha1.Operator, err error) error {
+	var noPathErr *upgradepath.NoPathError
+	if errors.As(err, &noPathErr) {
+		setInstalledStatusConditionNoUpgradePath(&op.Status.Conditions, err.Error(), op.GetGeneration())
+		return nil
+	}
+	setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+	return err
+}
+
+// setResolutionFailed records a resolution failure in the Operator's status
+// and returns err wrapped in a ResolutionError.
+func setResolutionFailed(op *operatorsv1alpha1.Operator, err error) error {
+	op.Status.UpgradeGraph = nil
+	op.Status.UpgradePath = nil
+	op.Status.InstalledBundleResource = ""
+	setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
+	op.Status.ResolvedBundleResource = ""
+	setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
+	return &ResolutionError{Err: err}
+}
+
+// checkCatalogAvailable returns a non-empty message if the Operator references
+// a catalog that does not exist or has not been unpacked.
+func (r *OperatorReconciler) checkCatalogAvailable(ctx context.Context, op *operatorsv1alpha1.Operator) (string, error) {
+	if op.Spec.Catalog == nil {
+		return "", nil
+	}
+	catalog := &catalogd.Catalog{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: op.Spec.Catalog.Name}, catalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("catalog %q not found", op.Spec.Catalog.Name), nil
+		}
+		return "", err
+	}
+	return catalogUnavailableMessage(catalog), nil
+}
+
+// unavailableCatalogs returns a message for every Catalog that has not been
+// unpacked, sorted by catalog name.
+func (r *OperatorReconciler) unavailableCatalogs(ctx context.Context) ([]string, error) {
+	catalogs := &catalogd.CatalogList{}
+	if err := r.Client.List(ctx, catalogs); err != nil {
+		return nil, err
+	}
+	sort.Slice(catalogs.Items, func(i, j int) bool {
+		return catalogs.Items[i].GetName() < catalogs.Items[j].GetName()
+	})
+	var msgs []string
+	for i := range catalogs.Items {
+		if msg := catalogUnavailableMessage(&catalogs.Items[i]); msg != "" {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, nil
+}
+
+// catalogUnavailableMessage returns a message describing why the catalog's
+// content is not available, or an empty string if it has been unpacked.
+func catalogUnavailableMessage(catalog *catalogd.Catalog) string {
+	if catalog.Status.Phase == catalogd.PhaseUnpacked {
+		return ""
+	}
+	phase := catalog.Status.Phase
+	if phase == "" {
+		phase = catalogd.PhasePending
+	}
+	msg := fmt.Sprintf("catalog %q is not available: phase is %s", catalog.GetName(), phase)
+	if cond := apimeta.FindStatusCondition(catalog.Status.Conditions, catalogd.TypeUnpacked); cond != nil && cond.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, cond.Message)
+	}
+	return msg
+}
+
+// checkCatalogSnapshot returns a non-empty message if the Operator pins a
+// catalog snapshot that the referenced Catalog does not currently serve.
+func (r *OperatorReconciler) checkCatalogSnapshot(ctx context.Context, op *operatorsv1alpha1.Operator) (string, error) {
+	if op.Spec.Catalog == nil || op.Spec.Catalog.Ref == "" {
+		return "", nil
+	}
+	catalog := &catalogd.Catalog{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: op.Spec.Catalog.Name}, catalog); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("catalog %q not found", op.Spec.Catalog.Name), nil
+		}
+		return "", err
+	}
+	var ref string
+	if rs := catalog.Status.ResolvedSource; rs != nil && rs.Image != nil {
+		ref = rs.Image.Ref
+	}
+	if ref != op.Spec.Catalog.Ref {
+		if ref == "" {
+			return fmt.Sprintf("catalog %q has not been unpacked, waiting for snapshot %q", op.Spec.Catalog.Name, op.Spec.Catalog.Ref), nil
+		}
+		return fmt.Sprintf("catalog %q is at snapshot %q, waiting for snapshot %q", op.Spec.Catalog.Name, ref, op.Spec.Catalog.Ref), nil
+	}
+	return "", nil
+}
+
+// checkDependencies returns a non-empty message describing what op is waiting
+// on if any Operator in its spec.dependsOn has not been installed, or if its
+// dependencies form a cycle.
+func (r *OperatorReconciler) checkDependencies(ctx context.Context, op *operatorsv1alpha1.Operator) (string, error) {
+	if len(op.Spec.DependsOn) == 0 {
+		return "", nil
+	}
+	operators := &operatorsv1alpha1.OperatorList{}
+	if err := r.Client.List(ctx, operators); err != nil {
+		return "", err
+	}
+	byName := make(map[string]*operatorsv1alpha1.Operator, len(operators.Items))
+	for i := range operators.Items {
+		byName[operators.Items[i].GetName()] = &operators.Items[i]
+	}
+	byName[op.GetName()] = op
+
+	if cycle := findDependencyCycle(op.GetName(), byName); cycle != nil {
+		return fmt.Sprintf("dependency cycle detected: %s", strings.Join(cycle, " -> ")), nil
+	}
+
+	var waiting []string
+	for _, name := range op.Spec.DependsOn {
+		dep, ok := byName[name]
+		if !ok {
+			waiting = append(waiting, fmt.Sprintf("%q (not found)", name))
+			continue
+		}
+		cond := apimeta.FindStatusCondition(dep.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.ObservedGeneration != dep.GetGeneration() {
+			waiting = append(waiting, fmt.Sprintf("%q (not installed)", name))
+		}
+	}
+	if len(waiting) > 0 {
+		return fmt.Sprintf("waiting for dependencies: %s", strings.Join(waiting, ", ")), nil
+	}
+	return "", nil
+}
+
+// resolvedBundleMetadata returns the catalog metadata of the resolved bundle,
+// or nil if the bundle's catalog or name is not known.
+func (r *OperatorReconciler) resolvedBundleMetadata(ctx context.Context, bundle *entity.BundleEntity) (*catalogd.BundleMetadata, error) {
+	catalog, err := bundle.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	bundleName, err := bundle.BundleName()
+	if err != nil {
+		return nil, err
+	}
+	if catalog.Name == "" || bundleName == "" {
+		return nil, nil
+	}
+	bundleMetadata := &catalogd.BundleMetadata{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%s", catalog.Name, bundleName)}, bundleMetadata); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return bundleMetadata, nil
+}
+
+// providedAPIs returns the APIs declared by the olm.gvk properties of the
+// resolved bundle. Its catalog metadata is used if known, as entities from
+// catalogd don't carry olm.gvk properties.
+func providedAPIs(bundle *entity.BundleEntity, bundleMetadata *catalogd.BundleMetadata) ([]metav1.GroupVersionKind, error) {
+	var apis []metav1.GroupVersionKind
+	if bundleMetadata != nil {
+		gvks, err := providedGVKs(bundleMetadata.Spec.Properties)
+		if err != nil {
+			return nil, err
+		}
+		for _, gvk := range gvks {
+			apis = append(apis, metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+		}
+		return apis, nil
+	}
+	gvks, err := bundle.ProvidedGVKs()
+	if err != nil {
+		return nil, err
+	}
+	for _, gvk := range gvks {
+		apis = append(apis, metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+	}
+	return apis, nil
+}
+
+// checkConstraints returns a non-empty message if the bundle's catalog metadata
+// declares olm.constraint cel rules that are not satisfied by any bundle
+// installed by another Operator.
+func (r *OperatorReconciler) checkConstraints(ctx context.Context, op *operatorsv1alpha1.Operator, bundleMetadata *catalogd.BundleMetadata) (string, error) {
+	if bundleMetadata == nil {
+		return "", nil
+	}
+	var required []constraints.Constraint
+	for _, prop := range bundleMetadata.Spec.Properties {
+		if prop.Type != constraints.PropertyConstraint {
+			continue
+		}
+		var c constraints.Constraint
+		if err := json.Unmarshal(prop.Value, &c); err != nil {
+			return "", fmt.Errorf("property '%s' ('%s') could not be parsed: %w", prop.Type, prop.Value, err)
+		}
+		if c.Cel != nil {
+			required = append(required, c)
+		}
+	}
+	if len(required) == 0 {
+		return "", nil
+	}
+
+	bundles, err := installedBundles(ctx, r.Client)
+	if err != nil {
+		return "", err
+	}
+	var installed [][]catalogd.Property
+	for name, b := range bundles {
+		if name != op.GetName() {
+			installed = append(installed, b.Properties)
+		}
+	}
+	r.evaluatorOnce.Do(func() {
+		r.evaluator, r.evaluatorErr = constraints.NewEvaluator()
+	})
+	if r.evaluatorErr != nil {
+		return "", r.evaluatorErr
+	}
+	var unsatisfied []string
+	for _, c := range required {
+		ok, err := r.evaluator.Satisfied(ctx, c.Cel.Rule, installed)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			msg := c.FailureMessage
+			if msg == "" {
+				msg = fmt.Sprintf("no installed operator satisfies %q", c.Cel.Rule)
+			}
+			unsatisfied = append(unsatisfied, msg)
+		}
+	}
+	if len(unsatisfied) > 0 {
+		return fmt.Sprintf("waiting for required capabilities: %s", strings.Join(unsatisfied, "; ")), nil
+	}
+	return "", nil
+}
+
+// checkAPIConflicts returns a non-empty message if an API provided by the
+// bundle is already recorded as provided by another Operator.
+func (r *OperatorReconciler) checkAPIConflicts(ctx context.Context, op *operatorsv1alpha1.Operator, bundleMetadata *catalogd.BundleMetadata) (string, error) {
+	if bundleMetadata == nil || !features.OperatorControllerFeatureGate.Enabled(features.ProvidedAPIIndex) {
+		return "", nil
+	}
+	gvks, err := providedGVKs(bundleMetadata.Spec.Properties)
+	if err != nil {
+		return "", err
+	}
+	var conflicts []string
+	seen := map[string]bool{}
+	for _, gvk := range gvks {
+		name := operatorsv1alpha1.ProvidedAPIName(gvk.Group, gvk.Kind)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		api := &operatorsv1alpha1.ProvidedAPI{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, api); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		for _, p := range api.Status.Providers {
+			if p.Operator != op.GetName() {
+				conflicts = append(conflicts, fmt.Sprintf("%s is already provided by operator %q", name, p.Operator))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Sprintf("api conflict: %s", strings.Join(conflicts, ", ")), nil
+	}
+	return "", nil
+}
+
+// findDependencyCycle returns the first dependency cycle reachable from start,
+// as the list of Operator names along the cycle, or nil if there is none.
+func findDependencyCycle(start string, byName map[string]*operatorsv1alpha1.Operator) []string {
+	var path []string
+	onPath := map[string]bool{}
+	visited := map[string]bool{}
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		if onPath[name] {
+			for i := range path {
+				if path[i] == name {
+					return append(append([]string{}, path[i:]...), name)
+				}
+			}
+		}
+		if visited[name] {
+			return nil
+		}
+		visited[name] = true
+		onPath[name] = true
+		path = append(path, name)
+		if o, ok := byName[name]; ok {
+			for _, dep := range o.Spec.DependsOn {
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		onPath[name] = false
+		return nil
+	}
+	return visit(start)
+}
+
+// upgradeGraphForBundle returns the upgrade edges around the bundle in its
+// channel, as published by the catalogd Package of the catalog the bundle was
+// resolved from. It returns nil if the catalog or the bundle's name in it is
+// unknown, or if the Package or channel can't be found.
+func (r *OperatorReconciler) upgradeGraphForBundle(ctx context.Context, bundle *entity.BundleEntity) (*operatorsv1alpha1.UpgradeGraph, error) {
+	catalog, err := bundle.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	bundleName, err := bundle.BundleName()
+	if err != nil {
+		return nil, err
+	}
+	if catalog.Name == "" || bundleName == "" {
+		return nil, nil
+	}
+	packageName, err := bundle.PackageName()
+	if err != nil {
+		return nil, err
+	}
+	channelName, err := bundle.ChannelName()
+	if err != nil {
+		return nil, err
+	}
+	version, err := bundle.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &catalogd.Package{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%s", catalog.Name, packageName)}, pkg); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	for _, ch := range pkg.Spec.Channels {
+		if ch.Name == channelName {
+			return upgradeGraph(ch, bundleName, *version), nil
+		}
+	}
+	return nil, nil
+}
+
+// bundleDeploymentOwnershipConflict returns a message describing why the
+// Operator may not manage the existing BundleDeployment named after it, or ""
+// if there is none or the Operator controls it. A BundleDeployment that
+// nothing controls is adopted with the Adopt adoption policy.
+func (r *OperatorReconciler) bundleDeploymentOwnershipConflict(ctx context.Context, op *operatorsv1alpha1.Operator) (string, error) {
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: op.GetName()}, bd); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	if metav1.IsControlledBy(bd, op) {
+		return "", nil
+	}
+	if owner := metav1.GetControllerOf(bd); owner != nil {
+		return fmt.Sprintf("BundleDeployment %q is controlled by %s %q", bd.GetName(), owner.Kind, owner.Name), nil
+	}
+	if op.Spec.AdoptionPolicy == operatorsv1alpha1.AdoptionPolicyAdopt {
+		return "", nil
+	}
+	return fmt.Sprintf("BundleDeployment %q was not created by this Operator; set spec.adoptionPolicy to %s to take it over",
+		bd.GetName(), operatorsv1alpha1.AdoptionPolicyAdopt), nil
+}
+
+// channelUpgrade is an upgrade from the bundle an Operator's BundleDeployment
+// has to the resolved bundle, within the resolved bundle's channel.
+type channelUpgrade struct {
+	bd          *rukpakv1alpha1.BundleDeployment
+	currentName string
+	bundleName  string
+	channel     catalogd.PackageChannel
+	images      map[string]string
+	versions    map[string]semver.Version
+}
+
+// pendingChannelUpgrade returns the upgrade the Operator's BundleDeployment
+// needs to have the resolved bundle. It returns nil for new installs, when the
+// BundleDeployment already has the resolved bundle, and when the current
+// bundle or the resolved bundle's channel is not in the resolved bundle's
+// catalog.
+func (r *OperatorReconciler) pendingChannelUpgrade(ctx context.Context, op *operatorsv1alpha1.Operator, bundle *entity.BundleEntity, bundleImage string) (*channelUpgrade, error) {
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: op.GetName()}, bd); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	var current string
+	if bd.Spec.Template != nil && bd.Spec.Template.Spec.Source.Image != nil {
+		current = bd.Spec.Template.Spec.Source.Image.Ref
+	}
+	if current == "" || current == bundleImage {
+		return nil, nil
+	}
+
+	catalog, err := bundle.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	bundleName, err := bundle.BundleName()
+	if err != nil {
+		return nil, err
+	}
+	if catalog.Name == "" || bundleName == "" {
+		return nil, nil
+	}
+	packageName, err := bundle.PackageName()
+	if err != nil {
+		return nil, err
+	}
+	channelName, err := bundle.ChannelName()
+	if err != nil {
+		return nil, err
+	}
+
+	bundleMetadatas := &catalogd.BundleMetadataList{}
+	if err := r.Client.List(ctx, bundleMetadatas); err != nil {
+		return nil, err
+	}
+	prefix := catalog.Name + "-"
+	upgrade := &channelUpgrade{
+		bd:         bd,
+		bundleName: bundleName,
+		images:     map[string]string{},
+		versions:   map[string]semver.Version{},
+	}
+	for _, bm := range bundleMetadatas.Items {
+		if bm.Spec.Catalog.Name != catalog.Name || bm.Spec.Package != packageName || !strings.HasPrefix(bm.GetName(), prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(bm.GetName(), prefix)
+		upgrade.images[name] = bm.Spec.Image
+		if bm.Spec.Image == current {
+			upgrade.currentName = name
+		}
+		if v, ok := bundleMetadataVersion(bm); ok {
+			upgrade.versions[name] = v
+		}
+	}
+	if upgrade.currentName == "" {
+		return nil, nil
 	}
 
-	// lookup the bundle entity in the solution that corresponds to the
-	// Operator's desired package name.
-	bundleEntity, err := r.getBundleEntityFromSolution(solution, op.Spec.PackageName)
-	if err != nil {
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
-		op.Status.ResolvedBundleResource = ""
-		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+	pkg := &catalogd.Package{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%s", catalog.Name, packageName)}, pkg); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, ch := range pkg.Spec.Channels {
+		if ch.Name == channelName {
+			upgrade.channel = ch
+			return upgrade, nil
+		}
 	}
+	return nil, nil
+}
 
-	// Get the bundle image reference for the bundle
-	bundleImage, err := bundleEntity.BundlePath()
+// nextUpgradeHop returns the bundle image to apply for an Operator with the
+// Sequential upgrade strategy and the bundles that remain to be installed
+// until the resolved bundle is. The next bundle on the upgrade path is only
+// returned once the BundleDeployment reports the current bundle as installed.
+// The resolved bundle is applied directly for the Direct strategy and when
+// there is no channel upgrade.
+func nextUpgradeHop(op *operatorsv1alpha1.Operator, upgrade *channelUpgrade, bundleImage string) (string, []string, error) {
+	if op.Spec.UpgradeStrategy != operatorsv1alpha1.UpgradeStrategySequential || upgrade == nil {
+		return bundleImage, nil, nil
+	}
+	path, err := upgradepath.Find(upgrade.channel, upgrade.versions, upgrade.currentName, upgrade.bundleName)
 	if err != nil {
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, "installation has not been attempted as resolution failed", op.GetGeneration())
-
-		op.Status.ResolvedBundleResource = ""
-		setResolvedStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+		return "", nil, err
+	}
+	if len(path) == 0 {
+		return bundleImage, nil, nil
 	}
 
-	// Now we can set the Resolved Condition, and the resolvedBundleSource field to the bundleImage value.
-	op.Status.ResolvedBundleResource = bundleImage
-	setResolvedStatusConditionSuccess(&op.Status.Conditions, fmt.Sprintf("resolved to %q", bundleImage), op.GetGeneration())
+	bd := upgrade.bd
+	cond := apimeta.FindStatusCondition(bd.Status.Conditions, rukpakv1alpha1.TypeInstalled)
+	if bd.Status.ObservedGeneration != bd.GetGeneration() || cond == nil || cond.Status != metav1.ConditionTrue {
+		return upgrade.images[upgrade.currentName], append([]string{upgrade.currentName}, path...), nil
+	}
+	next, ok := upgrade.images[path[0]]
+	if !ok {
+		return "", nil, fmt.Errorf("bundle %q on the upgrade path to %q was not found in the catalog", path[0], upgrade.bundleName)
+	}
+	return next, path, nil
+}
 
-	mediaType, err := bundleEntity.MediaType()
-	if err != nil {
-		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+// skippedVersions returns the versions of the channel's bundles that are newer
+// than the current bundle and older than the resolved bundle but are not
+// installed on the way to it, in ascending order. With the Sequential
+// strategy the bundles on the upgrade path are installed; with Direct none
+// are.
+func skippedVersions(op *operatorsv1alpha1.Operator, upgrade *channelUpgrade) ([]string, error) {
+	from, okFrom := upgrade.versions[upgrade.currentName]
+	to, okTo := upgrade.versions[upgrade.bundleName]
+	if !okFrom || !okTo {
+		return nil, nil
 	}
-	bundleProvisioner, err := mapBundleMediaTypeToBundleProvisioner(mediaType)
-	if err != nil {
-		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+	installed := map[string]bool{}
+	if op.Spec.UpgradeStrategy == operatorsv1alpha1.UpgradeStrategySequential {
+		path, err := upgradepath.Find(upgrade.channel, upgrade.versions, upgrade.currentName, upgrade.bundleName)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range path {
+			installed[name] = true
+		}
 	}
-	// Ensure a BundleDeployment exists with its bundle source from the bundle
-	// image we just looked up in the solution.
-	dep := r.generateExpectedBundleDeployment(*op, bundleImage, bundleProvisioner)
-	if err := r.ensureBundleDeployment(ctx, dep); err != nil {
-		// originally Reason: operatorsv1alpha1.ReasonInstallationFailed
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionFailed(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+	var skipped []semver.Version
+	for _, e := range upgrade.channel.Entries {
+		v, ok := upgrade.versions[e.Name]
+		if ok && !installed[e.Name] && v.GT(from) && v.LT(to) {
+			skipped = append(skipped, v)
+		}
 	}
+	semver.Sort(skipped)
+	var names []string
+	for _, v := range skipped {
+		names = append(names, v.String())
+	}
+	return names, nil
+}
 
-	// convert existing unstructured object into bundleDeployment for easier mapping of status.
-	existingTypedBundleDeployment := &rukpakv1alpha1.BundleDeployment{}
-	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(dep.UnstructuredContent(), existingTypedBundleDeployment); err != nil {
-		// originally Reason: operatorsv1alpha1.ReasonInstallationStatusUnknown
-		op.Status.InstalledBundleResource = ""
-		setInstalledStatusConditionUnknown(&op.Status.Conditions, err.Error(), op.GetGeneration())
-		return ctrl.Result{}, err
+// bundleMetadataVersion returns the version from the bundle's olm.package property.
+func bundleMetadataVersion(bm catalogd.BundleMetadata) (semver.Version, bool) {
+	for _, prop := range bm.Spec.Properties {
+		if prop.Type != property.TypePackage {
+			continue
+		}
+		var pkg property.Package
+		if err := json.Unmarshal(prop.Value, &pkg); err != nil {
+			return semver.Version{}, false
+		}
+		v, err := semver.Parse(pkg.Version)
+		return v, err == nil
 	}
+	return semver.Version{}, false
+}
 
-	// Let's set the proper Installed condition and InstalledBundleResource field based on the
-	// existing BundleDeployment object status.
-	mapBDStatusToInstalledCondition(existingTypedBundleDeployment, op)
+// upgradeGraph computes the successors of current and the heads of channel.
+func upgradeGraph(channel catalogd.PackageChannel, current string, currentVersion semver.Version) *operatorsv1alpha1.UpgradeGraph {
+	graph := &operatorsv1alpha1.UpgradeGraph{Channel: channel.Name, Current: current}
+	replacedOrSkipped := map[string]bool{}
+	for _, e := range channel.Entries {
+		if e.Replaces != "" {
+			replacedOrSkipped[e.Replaces] = true
+		}
+		for _, skip := range e.Skips {
+			replacedOrSkipped[skip] = true
+		}
+	}
+	for _, e := range channel.Entries {
+		if upgradepath.UpgradesFrom(e, current, &currentVersion) {
+			graph.Successors = append(graph.Successors, e.Name)
+		}
+	}
+	for _, e := range channel.Entries {
+		if !replacedOrSkipped[e.Name] {
+			graph.Heads = append(graph.Heads, e.Name)
+		}
+	}
+	sort.Strings(graph.Successors)
+	sort.Strings(graph.Heads)
+	return graph
+}
 
-	// set the status of the operator based on the respective bundle deployment status conditions.
-	return ctrl.Result{}, nil
+// operatorPhase derives status.phase from the Operator's current conditions.
+func operatorPhase(op *operatorsv1alpha1.Operator) string {
+	installed := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+	resolved := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeResolved)
+	current := func(c *metav1.Condition) bool {
+		return c != nil && c.ObservedGeneration == op.GetGeneration()
+	}
+	switch {
+	case validators.ValidateOperatorSpec(op) != nil:
+		// An invalid spec leaves both conditions Unknown, but it won't make
+		// progress until the spec is fixed.
+		return operatorsv1alpha1.PhaseFailed
+	case !current(installed) || !current(resolved):
+		return operatorsv1alpha1.PhaseProgressing
+	case installed.Status == metav1.ConditionTrue:
+		return operatorsv1alpha1.PhaseInstalled
+	case resolved.Status == metav1.ConditionFalse &&
+		resolved.Reason != operatorsv1alpha1.ReasonCatalogSnapshotUnavailable &&
+		resolved.Reason != operatorsv1alpha1.ReasonCatalogUnavailable:
+		return operatorsv1alpha1.PhaseFailed
+	case installed.Status == metav1.ConditionFalse &&
+		installed.Reason != operatorsv1alpha1.ReasonWaitingForDependencies &&
+		installed.Reason != operatorsv1alpha1.ReasonDeletionBlocked &&
+		installed.Reason != operatorsv1alpha1.ReasonWaitingForHooks:
+		return operatorsv1alpha1.PhaseFailed
+	}
+	return operatorsv1alpha1.PhaseProgressing
 }
 
 func mapBDStatusToInstalledCondition(existingTypedBundleDeployment *rukpakv1alpha1.BundleDeployment, op *operatorsv1alpha1.Operator) {
@@ -212,30 +1440,40 @@ func mapBDStatusToInstalledCondition(existingTypedBundleDeployment *rukpakv1alph
 	}
 
 	bundleDeploymentSource := existingTypedBundleDeployment.Spec.Template.Spec.Source
-	switch bundleDeploymentSource.Type {
-	case rukpakv1alpha1.SourceTypeImage:
-		op.Status.InstalledBundleResource = bundleDeploymentSource.Image.Ref
-		setInstalledStatusConditionSuccess(
-			&op.Status.Conditions,
-			fmt.Sprintf("installed from %q", bundleDeploymentSource.Image.Ref),
-			op.GetGeneration(),
-		)
-	case rukpakv1alpha1.SourceTypeGit:
-		resource := bundleDeploymentSource.Git.Repository + "@" + bundleDeploymentSource.Git.Ref.Commit
-		op.Status.InstalledBundleResource = resource
-		setInstalledStatusConditionSuccess(
-			&op.Status.Conditions,
-			fmt.Sprintf("installed from %q", resource),
-			op.GetGeneration(),
-		)
-	default:
+	resource := bundleSourceResource(bundleDeploymentSource)
+	if resource == "" {
 		op.Status.InstalledBundleResource = ""
 		setInstalledStatusConditionUnknown(
 			&op.Status.Conditions,
 			fmt.Sprintf("unknown bundledeployment source type %q", bundleDeploymentSource.Type),
 			op.GetGeneration(),
 		)
+		return
+	}
+	op.Status.InstalledBundleResource = resource
+	setInstalledStatusConditionSuccess(
+		&op.Status.Conditions,
+		fmt.Sprintf("installed from %q", resource),
+		op.GetGeneration(),
+	)
+}
+
+// bundleSourceResource returns the bundle resource reported in status for a
+// BundleDeployment source, or "" if the source type is not known.
+func bundleSourceResource(source rukpakv1alpha1.BundleSource) string {
+	switch source.Type {
+	case rukpakv1alpha1.SourceTypeImage:
+		return source.Image.Ref
+	case rukpakv1alpha1.SourceTypeGit:
+		return source.Git.Repository + "@" + source.Git.Ref.Commit
+	case rukpakv1alpha1.SourceTypeConfigMaps:
+		names := make([]string, 0, len(source.ConfigMaps))
+		for _, cm := range source.ConfigMaps {
+			names = append(names, cm.ConfigMap.Name)
+		}
+		return "configmaps/" + strings.Join(names, ",")
 	}
+	return ""
 }
 
 func (r *OperatorReconciler) getBundleEntityFromSolution(solution *solver.Solution, packageName string) (*entity.BundleEntity, error) {
@@ -254,6 +1492,53 @@ func (r *OperatorReconciler) getBundleEntityFromSolution(solution *solver.Soluti
 	return nil, fmt.Errorf("entity for package %q not found in solution", packageName)
 }
 
+// availableAfterClusterUpgrade returns the names of the bundles of the resolved
+// bundle's package that are newer than it but need the Kubernetes version the
+// cluster is being upgraded to.
+func availableAfterClusterUpgrade(solution *solver.Solution, resolved *entity.BundleEntity) ([]string, error) {
+	packageName, err := resolved.PackageName()
+	if err != nil {
+		return nil, err
+	}
+	resolvedVersion, err := resolved.Version()
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, variable := range solution.SelectedVariables() {
+		v, ok := variable.(*required_package.RequiredPackageVariable)
+		if !ok {
+			continue
+		}
+		for _, bundle := range v.AvailableAfterClusterUpgrade() {
+			if name, err := bundle.PackageName(); err != nil || name != packageName {
+				continue
+			}
+			version, err := bundle.Version()
+			if err != nil {
+				return nil, err
+			}
+			if !version.GT(*resolvedVersion) {
+				continue
+			}
+			name, err := bundle.BundleName()
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				name = version.String()
+			}
+			found[name] = true
+		}
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func (r *OperatorReconciler) generateExpectedBundleDeployment(o operatorsv1alpha1.Operator, bundlePath string, bundleProvisioner string) *unstructured.Unstructured {
 	// We use unstructured here to avoid problems of serializing default values when sending patches to the apiserver.
 	// If you use a typed object, any default values from that struct get serialized into the JSON patch, which could
@@ -297,27 +1582,83 @@ func (r *OperatorReconciler) generateExpectedBundleDeployment(o operatorsv1alpha
 	return bd
 }
 
+// setBundleDeploymentSource replaces the bundle source of the generated
+// BundleDeployment with the Operator's bundle override.
+func setBundleDeploymentSource(bd *unstructured.Unstructured, override *operatorsv1alpha1.BundleOverride) error {
+	source := map[string]interface{}{}
+	switch {
+	case override.Image != "":
+		source["type"] = string(rukpakv1alpha1.SourceTypeImage)
+		source["image"] = map[string]interface{}{"ref": override.Image}
+	case override.ConfigMap != "":
+		source["type"] = string(rukpakv1alpha1.SourceTypeConfigMaps)
+		source["configMaps"] = []interface{}{
+			map[string]interface{}{"configMap": map[string]interface{}{"name": override.ConfigMap}, "path": ""},
+		}
+	}
+	return unstructured.SetNestedField(bd.Object, source, "spec", "template", "spec", "source")
+}
+
+// reconcileRequestChanged passes updates that change an Operator's
+// reconcile annotation.
+var reconcileRequestChanged = predicate.Funcs{
+	CreateFunc:  func(event.CreateEvent) bool { return false },
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return false
+		}
+		return e.ObjectOld.GetAnnotations()[operatorsv1alpha1.ReconcileRequestAnnotation] !=
+			e.ObjectNew.GetAnnotations()[operatorsv1alpha1.ReconcileRequestAnnotation]
+	},
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *OperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	err := ctrl.NewControllerManagedBy(mgr).
-		For(&operatorsv1alpha1.Operator{}).
+	cfg := r.Config.Get()
+	r.startupJitter = newStartupJitter(cfg.StartupJitter.Duration, time.Now())
+	rateLimiter := workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(cfg.RequeueRateLimit.QPS), cfg.RequeueRateLimit.Burst)},
+	)
+	b := ctrl.NewControllerManagedBy(mgr).
+		// Status-only updates, including the ones made by this reconciler, don't
+		// change the outcome of a reconcile, so they aren't worth a global solve.
+		// Changes to the reconcile annotation request a repair.
+		// Label changes may change which OperatorDefaults select the Operator.
+		For(&operatorsv1alpha1.Operator{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, reconcileRequestChanged))).
+		Watches(&source.Kind{Type: &operatorsv1alpha1.OperatorDefaults{}},
+			handler.EnqueueRequestsFromMapFunc(r.invalidatingResolutionCache(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger())))).
 		Watches(source.NewKindWithCache(&catalogd.Catalog{}, mgr.GetCache()),
-			handler.EnqueueRequestsFromMapFunc(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger()))).
-		Owns(&rukpakv1alpha1.BundleDeployment{}).
-		Complete(r)
-
-	if err != nil {
-		return err
+			handler.EnqueueRequestsFromMapFunc(r.invalidatingResolutionCache(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger())))).
+		// Catalog content may reach the cache after the Catalog event announcing
+		// it, so Operators are requeued once it does. Only metadata is watched, as
+		// the resource versions are part of the resolution cache key.
+		Watches(&source.Kind{Type: &catalogd.BundleMetadata{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger())),
+			builder.OnlyMetadata).
+		Watches(&source.Kind{Type: &catalogd.Package{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForCatalog(context.TODO(), mgr.GetClient(), mgr.GetLogger())),
+			builder.OnlyMetadata).
+		Watches(&source.Kind{Type: &operatorsv1alpha1.Operator{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestsForDependents(context.TODO(), mgr.GetClient(), mgr.GetLogger()))).
+		// BundleDeployments are mapped to Operators by name rather than by owner,
+		// so that Operators with an ownership conflict are reconciled again once
+		// the conflicting BundleDeployment changes or is deleted.
+		Watches(&source.Kind{Type: &rukpakv1alpha1.BundleDeployment{}},
+			handler.EnqueueRequestsFromMapFunc(operatorRequestForBundleDeployment)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: cfg.MaxConcurrentReconciles, RateLimiter: rateLimiter})
+	if features.OperatorControllerFeatureGate.Enabled(features.LifecycleHooks) {
+		b = b.Owns(&batchv1.Job{})
 	}
-	return nil
+	return b.Complete(r)
 }
 
-func (r *OperatorReconciler) ensureBundleDeployment(ctx context.Context, desiredBundleDeployment *unstructured.Unstructured) error {
-	// TODO: what if there happens to be an unrelated BD with the same name as the Operator?
-	//   we should probably also check to see if there's an owner reference and/or a label set
-	//   that we expect only to ever be used by the operator controller. That way, we don't
-	//   automatically and silently adopt and change a BD that the user doens't intend to be
-	//   owned by the Operator.
+// ensureBundleDeployment applies desiredBundleDeployment unless the existing
+// BundleDeployment already has everything it has. With force, it is always
+// applied.
+func (r *OperatorReconciler) ensureBundleDeployment(ctx context.Context, desiredBundleDeployment *unstructured.Unstructured, force bool) error {
 	existingBundleDeployment, err := r.existingBundleDeploymentUnstructured(ctx, desiredBundleDeployment.GetName())
 	if client.IgnoreNotFound(err) != nil {
 		return err
@@ -325,12 +1666,31 @@ func (r *OperatorReconciler) ensureBundleDeployment(ctx context.Context, desired
 
 	// If the existing BD already has everything that the desired BD has, no need to contact the API server.
 	// Make sure the status of the existingBD from the server is as expected.
-	if equality.Semantic.DeepDerivative(desiredBundleDeployment, existingBundleDeployment) {
+	if !force && equality.Semantic.DeepDerivative(desiredBundleDeployment, existingBundleDeployment) {
 		*desiredBundleDeployment = *existingBundleDeployment
 		return nil
 	}
 
-	return r.Client.Patch(ctx, desiredBundleDeployment, client.Apply, client.ForceOwnership, client.FieldOwner("operator-controller"))
+	cfg := r.Config.Get()
+	patchOpts := []client.PatchOption{client.FieldOwner(cfg.FieldManager)}
+	if cfg.ApplyConflictPolicy != config.ApplyConflictPolicyFail {
+		patchOpts = append(patchOpts, client.ForceOwnership)
+	}
+	// Record when the BundleDeployment last changed; the progress deadline is
+	// measured from here. Reconciles that don't change it leave it as is.
+	annotations := desiredBundleDeployment.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AppliedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	desiredBundleDeployment.SetAnnotations(annotations)
+	if err := r.Client.Patch(ctx, desiredBundleDeployment, client.Apply, patchOpts...); err != nil {
+		if apierrors.IsConflict(err) {
+			err = fmt.Errorf("bundledeployment fields are owned by another field manager: %w", err)
+		}
+		return &ApplyError{Object: desiredBundleDeployment, Err: err}
+	}
+	return nil
 }
 
 func (r *OperatorReconciler) existingBundleDeploymentUnstructured(ctx context.Context, name string) (*unstructured.Unstructured, error) {
@@ -412,6 +1772,54 @@ func setResolvedStatusConditionFailed(conditions *[]metav1.Condition, message st
 	})
 }
 
+// setResolvedStatusConditionCatalogUnavailable sets the resolved status condition to
+// false because the catalog content needed for resolution is not available.
+func setResolvedStatusConditionCatalogUnavailable(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonCatalogUnavailable,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setResolvedStatusConditionRegressed sets the resolved status condition to
+// false because resolution regressed and the installed bundle is kept.
+func setResolvedStatusConditionRegressed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonResolutionRegressed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setResolvedStatusConditionCatalogSnapshotUnavailable sets the resolved status condition to
+// false because the pinned catalog snapshot is not available.
+func setResolvedStatusConditionCatalogSnapshotUnavailable(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonCatalogSnapshotUnavailable,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setResolvedStatusConditionPolicyViolation sets the resolved status condition to
+// false because the requested package is not allowed by the package policy.
+func setResolvedStatusConditionPolicyViolation(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonPolicyViolation,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
 // setResolvedStatusConditionUnknown sets the resolved status condition to unknown.
 func setResolvedStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
 	apimeta.SetStatusCondition(conditions, metav1.Condition{
@@ -445,6 +1853,128 @@ func setInstalledStatusConditionFailed(conditions *[]metav1.Condition, message s
 	})
 }
 
+// setInstalledStatusConditionWaitingForDependencies sets the installed status condition to
+// false because the Operator's dependencies have not been installed.
+func setInstalledStatusConditionWaitingForDependencies(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonWaitingForDependencies,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionWaitingForHooks sets the installed status condition to
+// false while the Operator's lifecycle hooks are running.
+func setInstalledStatusConditionWaitingForHooks(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonWaitingForHooks,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionHookFailed sets the installed status condition to
+// false because one of the Operator's lifecycle hooks failed.
+func setInstalledStatusConditionHookFailed(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonHookFailed,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionOverridden sets the installed status condition's reason to
+// Overridden while the Operator's bundle override is installed in place of the resolved
+// bundle. The status and message of the installation are kept.
+func setInstalledStatusConditionOverridden(conditions *[]metav1.Condition, override *operatorsv1alpha1.BundleOverride, resolved string, generation int64) {
+	status, message := metav1.ConditionUnknown, ""
+	if cond := apimeta.FindStatusCondition(*conditions, operatorsv1alpha1.TypeInstalled); cond != nil {
+		status, message = cond.Status, cond.Message
+	}
+	source := override.Image
+	if source == "" {
+		source = "configmaps/" + override.ConfigMap
+	}
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             status,
+		Reason:             operatorsv1alpha1.ReasonOverridden,
+		Message:            fmt.Sprintf("resolved bundle %q is overridden by %q: %s", resolved, source, message),
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionSkipNotAcknowledged sets the installed status condition to
+// false with reason SkipNotAcknowledged.
+func setInstalledStatusConditionSkipNotAcknowledged(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonSkipNotAcknowledged,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionNoUpgradePath sets the installed status condition to
+// false with reason NoUpgradePath.
+func setInstalledStatusConditionNoUpgradePath(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonNoUpgradePath,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionOwnershipConflict sets the installed status condition to
+// false with reason OwnershipConflict.
+func setInstalledStatusConditionOwnershipConflict(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonOwnershipConflict,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionDeletionBlocked sets the installed status condition's reason
+// to DeletionBlocked while the Operator's deletion is blocked by dependents. The status is
+// kept, as the bundle is neither installed nor removed by a blocked deletion.
+func setInstalledStatusConditionDeletionBlocked(conditions *[]metav1.Condition, message string, generation int64) {
+	status := metav1.ConditionUnknown
+	if cond := apimeta.FindStatusCondition(*conditions, operatorsv1alpha1.TypeInstalled); cond != nil {
+		status = cond.Status
+	}
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             status,
+		Reason:             operatorsv1alpha1.ReasonDeletionBlocked,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
+// setInstalledStatusConditionProgressDeadlineExceeded sets the installed status condition to
+// false because the bundle was not installed within the Operator's progress deadline.
+func setInstalledStatusConditionProgressDeadlineExceeded(conditions *[]metav1.Condition, message string, generation int64) {
+	apimeta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionFalse,
+		Reason:             operatorsv1alpha1.ReasonProgressDeadlineExceeded,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
+
 // setInstalledStatusConditionUnknown sets the installed status condition to unknown.
 func setInstalledStatusConditionUnknown(conditions *[]metav1.Condition, message string, generation int64) {
 	apimeta.SetStatusCondition(conditions, metav1.Condition{
@@ -456,6 +1986,15 @@ func setInstalledStatusConditionUnknown(conditions *[]metav1.Condition, message
 	})
 }
 
+// invalidatingResolutionCache wraps fn so that the resolution cache is
+// invalidated before the affected Operators are requeued.
+func (r *OperatorReconciler) invalidatingResolutionCache(fn handler.MapFunc) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		r.resolutionCache.invalidate()
+		return fn(object)
+	}
+}
+
 // Generate reconcile requests for all operators affected by a catalog change
 func operatorRequestsForCatalog(ctx context.Context, c client.Reader, logger logr.Logger) handler.MapFunc {
 	return func(object client.Object) []reconcile.Request {
@@ -478,3 +2017,40 @@ func operatorRequestsForCatalog(ctx context.Context, c client.Reader, logger log
 		return requests
 	}
 }
+
+// Generate reconcile requests for all operators that depend on the changed operator, and
+// for operators waiting on dependencies or capabilities that the change may provide
+func operatorRequestsForDependents(ctx context.Context, c client.Reader, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		operators := operatorsv1alpha1.OperatorList{}
+		err := c.List(ctx, &operators)
+		if err != nil {
+			logger.Error(err, "unable to enqueue dependents of operator")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, op := range operators.Items {
+			if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); cond != nil &&
+				cond.Reason == operatorsv1alpha1.ReasonWaitingForDependencies && op.GetName() != object.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: op.GetName()},
+				})
+				continue
+			}
+			for _, dep := range op.Spec.DependsOn {
+				if dep == object.GetName() {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: op.GetName()},
+					})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
+
+// Generate a reconcile request for the operator a bundle deployment is named after
+func operatorRequestForBundleDeployment(object client.Object) []reconcile.Request {
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: object.GetName()}}}
+}
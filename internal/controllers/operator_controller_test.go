@@ -2,18 +2,26 @@ package controllers_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/blang/semver/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/deppy/pkg/deppy/solver"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/rest"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,8 +29,10 @@ import (
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
 	"github.com/operator-framework/operator-controller/internal/conditionsets"
+	"github.com/operator-framework/operator-controller/internal/config"
 	"github.com/operator-framework/operator-controller/internal/controllers"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/olm"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/required_package"
 )
 
 var _ = Describe("Operator Controller Test", func() {
@@ -75,6 +85,11 @@ var _ = Describe("Operator Controller Test", func() {
 				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
 				Expect(res).To(Equal(ctrl.Result{}))
 				Expect(err).To(MatchError(fmt.Sprintf("package '%s' not found", pkgName)))
+				var resolutionErr *controllers.ResolutionError
+				Expect(errors.As(err, &resolutionErr)).To(BeTrue())
+				var notFoundErr *required_package.PackageNotFoundError
+				Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+				Expect(notFoundErr.PackageName).To(Equal(pkgName))
 
 				By("fetching updated operator after reconcile")
 				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
@@ -89,6 +104,7 @@ var _ = Describe("Operator Controller Test", func() {
 				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
 				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
 				Expect(cond.Message).To(Equal(fmt.Sprintf("package '%s' not found", pkgName)))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseFailed))
 			})
 		})
 		When("the operator specifies a version that does not exist", func() {
@@ -411,6 +427,10 @@ var _ = Describe("Operator Controller Test", func() {
 							By("Checking the status fields")
 							Expect(op.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
 							Expect(op.Status.InstalledBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+							Expect(op.Status.InstalledBundleProvenance).To(Equal(&operatorsv1alpha1.BundleProvenance{
+								Image:  "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed",
+								Digest: "sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed",
+							}))
 
 							By("checking the expected conditions")
 							cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeResolved)
@@ -423,6 +443,7 @@ var _ = Describe("Operator Controller Test", func() {
 							Expect(cond.Status).To(Equal(metav1.ConditionTrue))
 							Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
 							Expect(cond.Message).To(Equal("installed from \"quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed\""))
+							Expect(op.Status.Phase).To(Equal(operatorsv1alpha1.PhaseInstalled))
 						})
 
 						It("verify any other unknown status of bundledeployment", func() {
@@ -519,7 +540,19 @@ var _ = Describe("Operator Controller Test", func() {
 				BeforeEach(func() {
 					By("creating the expected BD")
 					bd = &rukpakv1alpha1.BundleDeployment{
-						ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+						ObjectMeta: metav1.ObjectMeta{
+							Name: opKey.Name,
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+									Kind:               "Operator",
+									Name:               operator.Name,
+									UID:                operator.UID,
+									Controller:         pointer.Bool(true),
+									BlockOwnerDeletion: pointer.Bool(true),
+								},
+							},
+						},
 						Spec: rukpakv1alpha1.BundleDeploymentSpec{
 							ProvisionerClassName: "foo",
 							Template: &rukpakv1alpha1.BundleTemplate{
@@ -575,6 +608,253 @@ var _ = Describe("Operator Controller Test", func() {
 					Expect(cond.Message).To(Equal("bundledeployment status is unknown"))
 				})
 			})
+			When("an out-of-date BundleDeployment exists and the conflict policy is Fail", func() {
+				BeforeEach(func() {
+					cfg := config.Default()
+					cfg.ApplyConflictPolicy = config.ApplyConflictPolicyFail
+					reconciler.Config = config.NewStore(cfg)
+
+					By("creating a BD owned by another field manager")
+					bd := &rukpakv1alpha1.BundleDeployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: opKey.Name,
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+									Kind:               "Operator",
+									Name:               operator.Name,
+									UID:                operator.UID,
+									Controller:         pointer.Bool(true),
+									BlockOwnerDeletion: pointer.Bool(true),
+								},
+							},
+						},
+						Spec: rukpakv1alpha1.BundleDeploymentSpec{
+							ProvisionerClassName: "foo",
+							Template: &rukpakv1alpha1.BundleTemplate{
+								Spec: rukpakv1alpha1.BundleSpec{
+									ProvisionerClassName: "bar",
+									Source: rukpakv1alpha1.BundleSource{
+										Type: rukpakv1alpha1.SourceTypeHTTP,
+										HTTP: &rukpakv1alpha1.HTTPSource{
+											URL: "http://localhost:8080/",
+										},
+									},
+								},
+							},
+						},
+					}
+					err := cl.Create(ctx, bd, client.FieldOwner("other-manager"))
+					Expect(err).NotTo(HaveOccurred())
+				})
+				It("does not take ownership of the conflicting fields", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).To(MatchError(ContainSubstring("bundledeployment fields are owned by another field manager")))
+					var applyErr *controllers.ApplyError
+					Expect(errors.As(err, &applyErr)).To(BeTrue())
+					Expect(applyErr.Object.GetName()).To(Equal(opKey.Name))
+					Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+					By("checking the BD spec is unchanged")
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.ProvisionerClassName).To(Equal("foo"))
+					Expect(bd.Spec.Template.Spec.ProvisionerClassName).To(Equal("bar"))
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected status conditions")
+					Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
+					Expect(cond.Message).To(ContainSubstring("bundledeployment fields are owned by another field manager"))
+				})
+			})
+			When("a BundleDeployment with the Operator's name was not created for it", func() {
+				BeforeEach(func() {
+					By("creating a BD without an owner")
+					bd := &rukpakv1alpha1.BundleDeployment{
+						ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+						Spec: rukpakv1alpha1.BundleDeploymentSpec{
+							ProvisionerClassName: "foo",
+							Template: &rukpakv1alpha1.BundleTemplate{
+								Spec: rukpakv1alpha1.BundleSpec{
+									ProvisionerClassName: "bar",
+									Source: rukpakv1alpha1.BundleSource{
+										Type: rukpakv1alpha1.SourceTypeHTTP,
+										HTTP: &rukpakv1alpha1.HTTPSource{
+											URL: "http://localhost:8080/",
+										},
+									},
+								},
+							},
+						},
+					}
+					Expect(cl.Create(ctx, bd)).To(Succeed())
+				})
+				It("leaves it untouched and reports an ownership conflict", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the BD spec is unchanged")
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.ProvisionerClassName).To(Equal("foo"))
+					Expect(bd.OwnerReferences).To(BeEmpty())
+
+					By("checking the expected status conditions")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonOwnershipConflict))
+					Expect(cond.Message).To(Equal(fmt.Sprintf("BundleDeployment %q was not created by this Operator; set spec.adoptionPolicy to Adopt to take it over", opKey.Name)))
+					Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseFailed))
+				})
+				It("records why the resolved bundle is not installed", func() {
+					const bundle = "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+					By("running reconcile twice")
+					for i := 0; i < 2; i++ {
+						_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					By("checking the same decision is recorded once")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					Expect(operator.Status.ResolutionHistory).To(HaveLen(1))
+					Expect(operator.Status.ResolutionHistory[0].Bundle).To(Equal(bundle))
+					Expect(operator.Status.ResolutionHistory[0].Reason).To(Equal(operatorsv1alpha1.ReasonOwnershipConflict))
+					Expect(operator.Status.ResolutionHistory[0].Time.IsZero()).To(BeFalse())
+
+					By("adopting the BD")
+					operator.Spec.AdoptionPolicy = operatorsv1alpha1.AdoptionPolicyAdopt
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the new reason is recorded")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					Expect(operator.Status.ResolutionHistory).To(HaveLen(2))
+					Expect(operator.Status.ResolutionHistory[1].Bundle).To(Equal(bundle))
+					Expect(operator.Status.ResolutionHistory[1].Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
+				})
+				It("adopts it with the Adopt adoption policy", func() {
+					By("setting the adoption policy")
+					operator.Spec.AdoptionPolicy = operatorsv1alpha1.AdoptionPolicyAdopt
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the BD is controlled by the Operator")
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(metav1.IsControlledBy(bd, operator)).To(BeTrue())
+					Expect(bd.Spec.ProvisionerClassName).To(Equal("core-rukpak-io-plain"))
+				})
+			})
+			When("an unowned BundleDeployment installs an older minor version", func() {
+				const installedImage = "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"
+				BeforeEach(func() {
+					By("creating a BD without an owner")
+					bd := &rukpakv1alpha1.BundleDeployment{
+						ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+						Spec: rukpakv1alpha1.BundleDeploymentSpec{
+							ProvisionerClassName: "core-rukpak-io-plain",
+							Template: &rukpakv1alpha1.BundleTemplate{
+								Spec: rukpakv1alpha1.BundleSpec{
+									ProvisionerClassName: "core-rukpak-io-registry",
+									Source: rukpakv1alpha1.BundleSource{
+										Type:  rukpakv1alpha1.SourceTypeImage,
+										Image: &rukpakv1alpha1.ImageSource{Ref: installedImage},
+									},
+								},
+							},
+						},
+					}
+					Expect(cl.Create(ctx, bd)).To(Succeed())
+					operator.Spec.UpgradePolicy = operatorsv1alpha1.UpgradePolicyZStream
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+				})
+				It("applies the ZStream constraint once the adoption policy is Adopt", func() {
+					By("running reconcile with the Refuse adoption policy")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+
+					By("adopting the BD")
+					operator.Spec.AdoptionPolicy = operatorsv1alpha1.AdoptionPolicyAdopt
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+					_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the installed minor version is kept")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					Expect(operator.Status.ResolvedBundleResource).To(Equal(installedImage))
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(installedImage))
+				})
+			})
+			When("a BundleDeployment with the Operator's name is controlled by another object", func() {
+				BeforeEach(func() {
+					By("creating a BD controlled by another Operator")
+					bd := &rukpakv1alpha1.BundleDeployment{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: opKey.Name,
+							OwnerReferences: []metav1.OwnerReference{
+								{
+									APIVersion: operatorsv1alpha1.GroupVersion.String(),
+									Kind:       "Operator",
+									Name:       "other",
+									UID:        "other-uid",
+									Controller: pointer.Bool(true),
+								},
+							},
+						},
+						Spec: rukpakv1alpha1.BundleDeploymentSpec{
+							ProvisionerClassName: "foo",
+							Template: &rukpakv1alpha1.BundleTemplate{
+								Spec: rukpakv1alpha1.BundleSpec{
+									ProvisionerClassName: "bar",
+									Source: rukpakv1alpha1.BundleSource{
+										Type: rukpakv1alpha1.SourceTypeHTTP,
+										HTTP: &rukpakv1alpha1.HTTPSource{
+											URL: "http://localhost:8080/",
+										},
+									},
+								},
+							},
+						},
+					}
+					Expect(cl.Create(ctx, bd)).To(Succeed())
+					operator.Spec.AdoptionPolicy = operatorsv1alpha1.AdoptionPolicyAdopt
+					Expect(cl.Update(ctx, operator)).To(Succeed())
+				})
+				It("does not adopt it", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("checking the expected status conditions")
+					Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonOwnershipConflict))
+					Expect(cond.Message).To(Equal(fmt.Sprintf("BundleDeployment %q is controlled by Operator \"other\"", opKey.Name)))
+				})
+			})
 		})
 		When("the selected bundle's image ref cannot be parsed", func() {
 			const pkgName = "badimage"
@@ -715,6 +995,75 @@ var _ = Describe("Operator Controller Test", func() {
 				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
 			})
 		})
+		When("operator defaults select the operator", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operatorDefaults := &operatorsv1alpha1.OperatorDefaults{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: operatorsv1alpha1.OperatorDefaultsSpec{
+						OperatorSelector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+						DeletionPolicy:   operatorsv1alpha1.DeletionPolicyBlock,
+					},
+				}
+				Expect(cl.Create(ctx, operatorDefaults)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(cl.Delete(ctx, operatorDefaults)).To(Succeed())
+				})
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name, Labels: map[string]string{"team": "a"}},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				DeferCleanup(func() {
+					op := &operatorsv1alpha1.Operator{}
+					if err := cl.Get(ctx, opKey, op); err == nil {
+						op.Finalizers = nil
+						Expect(cl.Update(ctx, op)).To(Succeed())
+					}
+				})
+			})
+			It("reconciles the operator with the defaults without writing them to its spec", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the default deletion policy is in effect")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).To(ContainElement(operatorsv1alpha1.DeletionProtectionFinalizer))
+				Expect(operator.Spec.DeletionPolicy).To(BeEmpty())
+
+				By("removing the label that selects the operator")
+				operator.SetLabels(nil)
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).NotTo(ContainElement(operatorsv1alpha1.DeletionProtectionFinalizer))
+			})
+		})
+		When("the operator specifies fallback channels", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Channels:    []operatorsv1alpha1.ChannelName{"stable", "beta"},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("resolves from the first channel that has a bundle", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the status fields")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"))
+				Expect(operator.Status.ResolvedChannel).To(Equal("beta"))
+			})
+		})
 		When("the operator specifies a package that exists within a channel but no version specified", func() {
 			var pkgName string
 			var pkgVer string
@@ -958,83 +1307,1639 @@ var _ = Describe("Operator Controller Test", func() {
 				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:plain"))
 			})
 		})
-		When("the operator specifies a package with a bad bundle mediatype", func() {
-			var pkgName string
-			var pkgVer string
-			var pkgChan string
+		When("the resolved bundle requires a capability from an installed operator", func() {
+			const prometheusImage = "quay.io/operatorhubio/prometheus@sha256:installed"
 			BeforeEach(func() {
 				By("initializing cluster state")
-				pkgName = "badmedia"
-				pkgVer = "0.1.0"
-				pkgChan = "beta"
+				createBundleMetadata(ctx, "operatorhub-plain.v0.1.0", "plain", "quay.io/operatorhub/plain@sha256:plain",
+					catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"plain","version":"0.1.0"}`)},
+					catalogd.Property{Type: "olm.constraint", Value: []byte(`{"failureMessage":"requires the Prometheus API","cel":{"rule":"properties.exists(p, p.type == 'olm.gvk' && p.value.kind == 'Prometheus')"}}`)},
+				)
+				createBundleMetadata(ctx, "operatorhub-prometheus.v0.47.0", "prometheus", prometheusImage,
+					catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"0.47.0"}`)},
+					catalogd.Property{Type: "olm.gvk", Value: []byte(`{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1"}`)},
+				)
 				operator = &operatorsv1alpha1.Operator{
 					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
-					Spec: operatorsv1alpha1.OperatorSpec{
-						PackageName: pkgName,
-						Version:     pkgVer,
-						Channel:     pkgChan,
-					},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "plain"},
 				}
-				err := cl.Create(ctx, operator)
-				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Create(ctx, operator)).To(Succeed())
 			})
-			It("sets resolution success status", func() {
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+			})
+			It("waits until an installed operator provides the capability", func() {
 				By("running reconcile")
 				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
 				Expect(res).To(Equal(ctrl.Result{}))
-				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("unknown bundle mediatype: badmedia+v1"))
-
-				By("fetching updated operator after reconcile")
-				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
-
-				By("Checking the status fields")
-				Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhub/badmedia@sha256:badmedia"))
-				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+				Expect(err).NotTo(HaveOccurred())
 
 				By("checking the expected conditions")
-				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
-				Expect(cond).NotTo(BeNil())
-				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
-				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
-				Expect(cond.Message).To(Equal("resolved to \"quay.io/operatorhub/badmedia@sha256:badmedia\""))
-				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
 				Expect(cond).NotTo(BeNil())
 				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
-				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
-				Expect(cond.Message).To(Equal("unknown bundle mediatype: badmedia+v1"))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForDependencies))
+				Expect(cond.Message).To(Equal("waiting for required capabilities: requires the Prometheus API"))
+
+				By("checking no BundleDeployment was created")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(apierrors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd))).To(BeTrue())
+
+				By("installing an operator that provides the capability")
+				prometheus := &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: "prometheus"},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, prometheus)).To(Succeed())
+				prometheus.Status.InstalledBundleResource = prometheusImage
+				apimeta.SetStatusCondition(&prometheus.Status.Conditions, metav1.Condition{
+					Type:               operatorsv1alpha1.TypeInstalled,
+					Status:             metav1.ConditionTrue,
+					Reason:             operatorsv1alpha1.ReasonSuccess,
+					Message:            "installed",
+					ObservedGeneration: prometheus.GetGeneration(),
+				})
+				Expect(cl.Status().Update(ctx, prometheus)).To(Succeed())
+
+				By("running reconcile again")
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the BundleDeployment was created")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
 			})
 		})
-		When("an invalid semver is provided that bypasses the regex validation", func() {
-			var (
-				pkgName    string
-				fakeClient client.Client
-			)
+		When("an API provided by the resolved bundle is already provided by another operator", func() {
 			BeforeEach(func() {
-				opKey = types.NamespacedName{Name: fmt.Sprintf("operator-validation-test-%s", rand.String(8))}
-
-				By("injecting creating a client with the bad operator CR")
-				pkgName = fmt.Sprintf("exists-%s", rand.String(6))
+				By("initializing cluster state")
+				createBundleMetadata(ctx, "operatorhub-plain.v0.1.0", "plain", "quay.io/operatorhub/plain@sha256:plain",
+					catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"plain","version":"0.1.0"}`)},
+					catalogd.Property{Type: "olm.gvk", Value: []byte(`{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1"}`)},
+				)
+				api := &operatorsv1alpha1.ProvidedAPI{
+					ObjectMeta: metav1.ObjectMeta{Name: "prometheus.monitoring.coreos.com"},
+					Spec:       operatorsv1alpha1.ProvidedAPISpec{Group: "monitoring.coreos.com", Kind: "Prometheus"},
+				}
+				Expect(cl.Create(ctx, api)).To(Succeed())
+				api.Status.Providers = []operatorsv1alpha1.APIProvider{
+					{Operator: "prometheus", InstalledBundleResource: "quay.io/operatorhubio/prometheus@sha256:installed", Versions: []string{"v1"}},
+				}
+				Expect(cl.Status().Update(ctx, api)).To(Succeed())
 				operator = &operatorsv1alpha1.Operator{
 					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
-					Spec: operatorsv1alpha1.OperatorSpec{
-						PackageName: pkgName,
-						Version:     "1.2.3-123abc_def", // bad semver that matches the regex on the CR validation
-					},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "plain"},
 				}
-
-				// this bypasses client/server-side CR validation and allows us to test the reconciler's validation
-				fakeClient = fake.NewClientBuilder().WithScheme(sch).WithObjects(operator).Build()
-
-				By("changing the reconciler client to the fake client")
-				reconciler.Client = fakeClient
+				Expect(cl.Create(ctx, operator)).To(Succeed())
 			})
-
-			It("should add an invalid spec condition and *not* re-enqueue for reconciliation", func() {
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+				Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.ProvidedAPI{})).To(Succeed())
+			})
+			It("fails the installation without creating a BundleDeployment", func() {
 				By("running reconcile")
 				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
 				Expect(res).To(Equal(ctrl.Result{}))
-				Expect(err).ToNot(HaveOccurred())
+				Expect(err).To(MatchError(`api conflict: prometheus.monitoring.coreos.com is already provided by operator "prometheus"`))
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
+				Expect(cond.Message).To(Equal(`api conflict: prometheus.monitoring.coreos.com is already provided by operator "prometheus"`))
+
+				By("checking the provided APIs of the resolved bundle are reported")
+				Expect(operator.Status.ProvidedAPIs).To(Equal([]metav1.GroupVersionKind{
+					{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus"},
+				}))
+
+				By("checking no BundleDeployment was created")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(apierrors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd))).To(BeTrue())
+			})
+		})
+		When("the resolution inputs are unchanged between reconciles", func() {
+			var entitySource *countingEntitySource
+			BeforeEach(func() {
+				By("initializing cluster state")
+				entitySource = &countingEntitySource{EntitySource: testEntitySource}
+				reconciler.Resolver = solver.NewDeppySolver(entitySource, olm.NewOLMVariableSource(cl))
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("reuses the previous resolution until an operator's spec changes", func() {
+				By("running reconcile until the BD is created")
+				for i := 0; i < 2; i++ {
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				calls := entitySource.calls
+				Expect(calls).To(BeNumerically(">", 0))
+
+				By("running reconcile again")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(Equal(calls))
+
+				By("changing the operator's spec")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				operator.Spec.Version = "0.37.0"
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile after the spec change")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(BeNumerically(">", calls))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"))
+			})
+			It("makes no writes when reconciled again without changes", func() {
+				By("running reconcile until the BD is created")
+				for i := 0; i < 2; i++ {
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				calls := entitySource.calls
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+
+				By("running reconcile as a watch on another object would")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking nothing was resolved or written")
+				Expect(entitySource.calls).To(Equal(calls))
+				current := &operatorsv1alpha1.Operator{}
+				Expect(cl.Get(ctx, opKey, current)).To(Succeed())
+				Expect(current.GetResourceVersion()).To(Equal(operator.GetResourceVersion()))
+				currentBD := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, currentBD)).To(Succeed())
+				Expect(currentBD.GetResourceVersion()).To(Equal(bd.GetResourceVersion()))
+			})
+			It("resolves again once catalog content the previous resolution missed arrives", func() {
+				defer func() {
+					Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+				}()
+				By("running reconcile until the BD is created")
+				createBundleMetadata(ctx, "operatorhub-prometheus.v0.47.0", "prometheus", "quay.io/operatorhubio/prometheus:v0.47.0")
+				for i := 0; i < 2; i++ {
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				calls := entitySource.calls
+
+				By("updating the bundle metadata after the catalog was reconciled")
+				bm := &catalogd.BundleMetadata{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: "operatorhub-prometheus.v0.47.0"}, bm)).To(Succeed())
+				bm.Spec.Image = "quay.io/operatorhubio/prometheus:v0.47.0-fixed"
+				Expect(cl.Update(ctx, bm)).To(Succeed())
+
+				By("running reconcile after the update")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(BeNumerically(">", calls))
+
+				By("running reconcile again")
+				calls = entitySource.calls
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(Equal(calls))
+			})
+			It("resolves again once the cluster's Kubernetes version changes", func() {
+				kubeVersion := semver.MustParse("1.26.0")
+				readKubeVersion := func(context.Context) (semver.Version, error) { return kubeVersion, nil }
+				reconciler.KubeVersion = readKubeVersion
+				reconciler.Resolver = solver.NewDeppySolver(entitySource, olm.NewOLMVariableSource(cl, olm.WithKubeVersionFunc(readKubeVersion, nil)))
+
+				By("running reconcile until the BD is created")
+				for i := 0; i < 2; i++ {
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				calls := entitySource.calls
+
+				By("upgrading the cluster")
+				kubeVersion = semver.MustParse("1.27.0")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(BeNumerically(">", calls))
+
+				By("running reconcile again")
+				calls = entitySource.calls
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entitySource.calls).To(Equal(calls))
+			})
+		})
+		When("the operator sets a progress deadline", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:             "plain",
+						ProgressDeadlineSeconds: pointer.Int32(600),
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("requeues until the deadline and then sets installation failed status", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+				Expect(res.RequeueAfter).To(BeNumerically("<=", 600*time.Second))
+
+				By("checking the BundleDeployment records when it was applied")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.GetAnnotations()).To(HaveKey(controllers.AppliedAtAnnotation))
+
+				By("checking the operator is still progressing")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseProgressing))
+
+				By("moving the apply time past the deadline")
+				bd.Annotations[controllers.AppliedAtAnnotation] = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+				Expect(cl.Update(ctx, bd)).To(Succeed())
+
+				By("running reconcile again")
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonProgressDeadlineExceeded))
+				Expect(cond.Message).To(Equal("bundledeployment was not installed within 10m0s of being applied: bundledeployment status is unknown"))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseFailed))
+			})
+		})
+		When("resolution does not finish within the resolution timeout", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				cfg := config.Default()
+				cfg.Timeouts.Resolution = metav1.Duration{Duration: time.Nanosecond}
+				reconciler.Config = config.NewStore(cfg)
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("reports that the resolution phase timed out", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).To(HaveOccurred())
+				var timeoutErr *controllers.TimeoutError
+				Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+				Expect(timeoutErr.Phase).To(Equal("resolution"))
+				var resolutionErr *controllers.ResolutionError
+				Expect(errors.As(err, &resolutionErr)).To(BeTrue())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+				Expect(cond.Message).To(HavePrefix("resolution timed out after 1ns: "))
+			})
+		})
+		When("the operator specifies a package denied by the package policy", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				cfg := config.Default()
+				cfg.PackagePolicy.Deny = []string{"prometheus"}
+				reconciler.Config = config.NewStore(cfg)
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("sets policy violation status and does not create a BundleDeployment", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the status fields")
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(""))
+				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonPolicyViolation))
+				Expect(cond.Message).To(Equal(`package "prometheus" is denied by pattern "prometheus"`))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
+
+				By("checking no BundleDeployment was created")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)
+				Expect(apierrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+		When("the operator depends on other operators", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						DependsOn:   []string{"dependency"},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			When("the dependency does not exist", func() {
+				It("waits for the dependency without creating a BundleDeployment", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForDependencies))
+					Expect(cond.Message).To(Equal(`waiting for dependencies: "dependency" (not found)`))
+					Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseProgressing))
+
+					By("checking no BundleDeployment was created")
+					err = cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})
+					Expect(apierrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+			When("the dependency is not installed", func() {
+				BeforeEach(func() {
+					createDependencyOperator(ctx, "dependency", metav1.ConditionFalse)
+				})
+				It("waits for the dependency", func() {
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForDependencies))
+					Expect(cond.Message).To(Equal(`waiting for dependencies: "dependency" (not installed)`))
+				})
+			})
+			When("the dependency is installed", func() {
+				BeforeEach(func() {
+					createDependencyOperator(ctx, "dependency", metav1.ConditionTrue)
+				})
+				It("creates the BundleDeployment", func() {
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Reason).NotTo(Equal(operatorsv1alpha1.ReasonWaitingForDependencies))
+
+					By("checking the BundleDeployment was created")
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, &rukpakv1alpha1.BundleDeployment{})).To(Succeed())
+				})
+			})
+			When("the dependencies form a cycle", func() {
+				BeforeEach(func() {
+					dep := &operatorsv1alpha1.Operator{
+						ObjectMeta: metav1.ObjectMeta{Name: "dependency"},
+						Spec: operatorsv1alpha1.OperatorSpec{
+							PackageName: "plain",
+							DependsOn:   []string{opKey.Name},
+						},
+					}
+					Expect(cl.Create(ctx, dep)).To(Succeed())
+				})
+				It("reports the cycle", func() {
+					By("running reconcile")
+					_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForDependencies))
+					Expect(cond.Message).To(Equal(fmt.Sprintf("dependency cycle detected: %[1]s -> dependency -> %[1]s", opKey.Name)))
+				})
+			})
+		})
+		When("the operator pins a catalog snapshot", func() {
+			const (
+				catalogName = "operatorhub"
+				catalogRef  = "quay.io/operatorhubio/catalog@sha256:snapshot"
+			)
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "plain",
+						Catalog: &operatorsv1alpha1.CatalogReference{
+							Name: catalogName,
+							Ref:  catalogRef,
+						},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Catalog{})).To(Succeed())
+			})
+			When("the catalog does not exist", func() {
+				It("sets catalog unavailable status and does not requeue", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the status fields")
+					Expect(operator.Status.ResolvedBundleResource).To(Equal(""))
+					Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonCatalogUnavailable))
+					Expect(cond.Message).To(Equal(`catalog "operatorhub" not found`))
+					cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
+					Expect(cond.Message).To(Equal("installation has not been attempted as resolution failed"))
+				})
+			})
+			When("the catalog serves a different snapshot", func() {
+				BeforeEach(func() {
+					createCatalogWithResolvedRef(ctx, catalogName, "quay.io/operatorhubio/catalog@sha256:other")
+				})
+				It("sets catalog snapshot unavailable status", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonCatalogSnapshotUnavailable))
+					Expect(cond.Message).To(Equal(`catalog "operatorhub" is at snapshot "quay.io/operatorhubio/catalog@sha256:other", waiting for snapshot "quay.io/operatorhubio/catalog@sha256:snapshot"`))
+				})
+			})
+			When("the catalog serves the pinned snapshot", func() {
+				BeforeEach(func() {
+					createCatalogWithResolvedRef(ctx, catalogName, catalogRef)
+				})
+				It("resolves a bundle from the pinned snapshot", func() {
+					By("running reconcile")
+					res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+					Expect(res).To(Equal(ctrl.Result{}))
+					Expect(err).NotTo(HaveOccurred())
+
+					By("fetching updated operator after reconcile")
+					Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+					By("checking the status fields")
+					Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhub/plain@sha256:plain"))
+
+					By("checking the expected conditions")
+					cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+					Expect(cond).NotTo(BeNil())
+					Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+					Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
+				})
+			})
+		})
+		When("the operator references a catalog that is still unpacking", func() {
+			const catalogName = "operatorhub"
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "plain",
+						Catalog:     &operatorsv1alpha1.CatalogReference{Name: catalogName},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				createUnpackingCatalog(ctx, catalogName)
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Catalog{})).To(Succeed())
+			})
+			It("sets catalog unavailable status and recovers once the catalog is unpacked", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonCatalogUnavailable))
+				Expect(cond.Message).To(Equal(`catalog "operatorhub" is not available: phase is Unpacking: unpacking catalog image`))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseProgressing))
+
+				By("marking the catalog as unpacked")
+				catalog := &catalogd.Catalog{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: catalogName}, catalog)).To(Succeed())
+				catalog.Status.Phase = catalogd.PhaseUnpacked
+				Expect(cl.Status().Update(ctx, catalog)).To(Succeed())
+
+				By("running reconcile again")
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the operator resolved")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
+			})
+		})
+		When("a package is not found while a catalog is still unpacking", func() {
+			const catalogName = "operatorhub"
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "not-yet-available"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				createUnpackingCatalog(ctx, catalogName)
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Catalog{})).To(Succeed())
+			})
+			It("sets catalog unavailable status and does not return an error", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonCatalogUnavailable))
+				Expect(cond.Message).To(Equal(`package 'not-yet-available' not found; catalog "operatorhub" is not available: phase is Unpacking: unpacking catalog image`))
+			})
+		})
+		When("the catalog publishes the upgrade graph of the resolved bundle", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				pkg := &catalogd.Package{
+					ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-plain"},
+					Spec: catalogd.PackageSpec{
+						Catalog:        corev1.LocalObjectReference{Name: "operatorhub"},
+						Name:           "plain",
+						DefaultChannel: "beta",
+						Channels: []catalogd.PackageChannel{
+							{
+								Name: "beta",
+								Entries: []catalogd.ChannelEntry{
+									{Name: "plain.v0.1.0"},
+									{Name: "plain.v0.2.0", Replaces: "plain.v0.1.0"},
+									{Name: "plain.v0.3.0", Replaces: "plain.v0.2.0", SkipRange: "<0.3.0"},
+									{Name: "plain.v0.2.1", Skips: []string{"plain.v0.2.0"}},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, pkg)).To(Succeed())
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "plain"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Package{})).To(Succeed())
+			})
+			It("publishes the successors and channel heads in status", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the upgrade graph")
+				Expect(operator.Status.UpgradeGraph).To(Equal(&operatorsv1alpha1.UpgradeGraph{
+					Channel:    "beta",
+					Current:    "plain.v0.1.0",
+					Successors: []string{"plain.v0.2.0", "plain.v0.3.0"},
+					Heads:      []string{"plain.v0.2.1", "plain.v0.3.0"},
+				}))
+			})
+		})
+		When("the operator is upgraded sequentially to a bundle that does not replace the installed bundle", func() {
+			var bd *rukpakv1alpha1.BundleDeployment
+			BeforeEach(func() {
+				By("initializing cluster state")
+				pkg := &catalogd.Package{
+					ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-plain"},
+					Spec: catalogd.PackageSpec{
+						Catalog:        corev1.LocalObjectReference{Name: "operatorhub"},
+						Name:           "plain",
+						DefaultChannel: "beta",
+						Channels: []catalogd.PackageChannel{
+							{
+								Name: "beta",
+								Entries: []catalogd.ChannelEntry{
+									{Name: "plain.v0.0.1"},
+									{Name: "plain.v0.0.2", Replaces: "plain.v0.0.1"},
+									{Name: "plain.v0.1.0", Replaces: "plain.v0.0.2"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, pkg)).To(Succeed())
+				for _, version := range []string{"0.0.1", "0.0.2", "0.1.0"} {
+					image := "quay.io/operatorhub/plain@sha256:" + version
+					if version == "0.1.0" {
+						image = "quay.io/operatorhub/plain@sha256:plain"
+					}
+					createBundleMetadata(ctx, "operatorhub-plain.v"+version, "plain", image,
+						catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"plain","version":"` + version + `"}`)},
+					)
+				}
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:     "plain",
+						UpgradeStrategy: operatorsv1alpha1.UpgradeStrategySequential,
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				bd = &rukpakv1alpha1.BundleDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: opKey.Name,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+								Kind:               "Operator",
+								Name:               operator.Name,
+								UID:                operator.UID,
+								Controller:         pointer.Bool(true),
+								BlockOwnerDeletion: pointer.Bool(true),
+							},
+						},
+					},
+					Spec: rukpakv1alpha1.BundleDeploymentSpec{
+						ProvisionerClassName: "core-rukpak-io-plain",
+						Template: &rukpakv1alpha1.BundleTemplate{
+							Spec: rukpakv1alpha1.BundleSpec{
+								ProvisionerClassName: "core-rukpak-io-plain",
+								Source: rukpakv1alpha1.BundleSource{
+									Type:  rukpakv1alpha1.SourceTypeImage,
+									Image: &rukpakv1alpha1.ImageSource{Ref: "quay.io/operatorhub/plain@sha256:0.0.1"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Package{})).To(Succeed())
+				Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+			})
+			It("installs every bundle on the upgrade path in turn", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the intermediate bundle is applied")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:0.0.2"))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.UpgradePath).To(Equal([]string{"plain.v0.0.2", "plain.v0.1.0"}))
+
+				By("running reconcile before the intermediate bundle is installed")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:0.0.2"))
+
+				By("running reconcile once the intermediate bundle is installed")
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:plain"))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.UpgradePath).To(Equal([]string{"plain.v0.1.0"}))
+			})
+		})
+		When("the operator is upgraded sequentially to a bundle with no upgrade path from the installed bundle", func() {
+			var bd *rukpakv1alpha1.BundleDeployment
+			BeforeEach(func() {
+				By("initializing cluster state")
+				pkg := &catalogd.Package{
+					ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-plain"},
+					Spec: catalogd.PackageSpec{
+						Catalog:        corev1.LocalObjectReference{Name: "operatorhub"},
+						Name:           "plain",
+						DefaultChannel: "beta",
+						Channels: []catalogd.PackageChannel{
+							{
+								Name: "beta",
+								Entries: []catalogd.ChannelEntry{
+									{Name: "plain.v0.0.1"},
+									{Name: "plain.v0.1.0"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, pkg)).To(Succeed())
+				for _, version := range []string{"0.0.1", "0.1.0"} {
+					image := "quay.io/operatorhub/plain@sha256:" + version
+					if version == "0.1.0" {
+						image = "quay.io/operatorhub/plain@sha256:plain"
+					}
+					createBundleMetadata(ctx, "operatorhub-plain.v"+version, "plain", image,
+						catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"plain","version":"` + version + `"}`)},
+					)
+				}
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:     "plain",
+						UpgradeStrategy: operatorsv1alpha1.UpgradeStrategySequential,
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				bd = &rukpakv1alpha1.BundleDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: opKey.Name,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+								Kind:               "Operator",
+								Name:               operator.Name,
+								UID:                operator.UID,
+								Controller:         pointer.Bool(true),
+								BlockOwnerDeletion: pointer.Bool(true),
+							},
+						},
+					},
+					Spec: rukpakv1alpha1.BundleDeploymentSpec{
+						ProvisionerClassName: "core-rukpak-io-plain",
+						Template: &rukpakv1alpha1.BundleTemplate{
+							Spec: rukpakv1alpha1.BundleSpec{
+								ProvisionerClassName: "core-rukpak-io-plain",
+								Source: rukpakv1alpha1.BundleSource{
+									Type:  rukpakv1alpha1.SourceTypeImage,
+									Image: &rukpakv1alpha1.ImageSource{Ref: "quay.io/operatorhub/plain@sha256:0.0.1"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Package{})).To(Succeed())
+				Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+			})
+			It("sets the Installed condition reason to NoUpgradePath without applying anything", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonNoUpgradePath))
+				Expect(cond.Message).To(Equal(`no upgrade path from "plain.v0.0.1" to "plain.v0.1.0" in channel "beta"`))
+
+				By("checking the installed bundle is left in place")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:0.0.1"))
+			})
+		})
+		When("the operator is upgraded to a bundle that skips versions of its channel", func() {
+			var bd *rukpakv1alpha1.BundleDeployment
+			BeforeEach(func() {
+				By("initializing cluster state")
+				pkg := &catalogd.Package{
+					ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-plain"},
+					Spec: catalogd.PackageSpec{
+						Catalog:        corev1.LocalObjectReference{Name: "operatorhub"},
+						Name:           "plain",
+						DefaultChannel: "beta",
+						Channels: []catalogd.PackageChannel{
+							{
+								Name: "beta",
+								Entries: []catalogd.ChannelEntry{
+									{Name: "plain.v0.0.1"},
+									{Name: "plain.v0.0.2", Replaces: "plain.v0.0.1"},
+									{Name: "plain.v0.1.0", Replaces: "plain.v0.0.2", SkipRange: "<0.1.0"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, pkg)).To(Succeed())
+				for _, version := range []string{"0.0.1", "0.0.2", "0.1.0"} {
+					image := "quay.io/operatorhub/plain@sha256:" + version
+					if version == "0.1.0" {
+						image = "quay.io/operatorhub/plain@sha256:plain"
+					}
+					createBundleMetadata(ctx, "operatorhub-plain.v"+version, "plain", image,
+						catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"plain","version":"` + version + `"}`)},
+					)
+				}
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:             "plain",
+						UpgradeConstraintPolicy: operatorsv1alpha1.UpgradeConstraintPolicyEnforce,
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				bd = &rukpakv1alpha1.BundleDeployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: opKey.Name,
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+								Kind:               "Operator",
+								Name:               operator.Name,
+								UID:                operator.UID,
+								Controller:         pointer.Bool(true),
+								BlockOwnerDeletion: pointer.Bool(true),
+							},
+						},
+					},
+					Spec: rukpakv1alpha1.BundleDeploymentSpec{
+						ProvisionerClassName: "core-rukpak-io-plain",
+						Template: &rukpakv1alpha1.BundleTemplate{
+							Spec: rukpakv1alpha1.BundleSpec{
+								ProvisionerClassName: "core-rukpak-io-plain",
+								Source: rukpakv1alpha1.BundleSource{
+									Type:  rukpakv1alpha1.SourceTypeImage,
+									Image: &rukpakv1alpha1.ImageSource{Ref: "quay.io/operatorhub/plain@sha256:0.0.1"},
+								},
+							},
+						},
+					},
+				}
+				Expect(cl.Create(ctx, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Package{})).To(Succeed())
+				Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+			})
+			It("only upgrades once the skip is acknowledged", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the upgrade is blocked")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSkipNotAcknowledged))
+				Expect(cond.Message).To(Equal(`upgrade to "plain.v0.1.0" skips versions 0.0.2; set spec.upgradeConstraintPolicy to AllowSkip to allow it`))
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:0.0.1"))
+
+				By("acknowledging the skip")
+				operator.Spec.UpgradeConstraintPolicy = operatorsv1alpha1.UpgradeConstraintPolicyAllowSkip
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the upgrade is applied and the skipped versions are recorded")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal("quay.io/operatorhub/plain@sha256:plain"))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.SkippedVersions).To(Equal([]string{"0.0.2"}))
+			})
+		})
+		When("the operator's bundle is overridden", func() {
+			const (
+				resolvedImage = "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+				hotfixImage   = "quay.io/operatorhubio/prometheus@sha256:hotfix"
+			)
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:    "prometheus",
+						BundleOverride: &operatorsv1alpha1.BundleOverride{Image: hotfixImage},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("installs the override until it is removed", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the override is applied")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(hotfixImage))
+
+				By("running reconcile once the override is installed")
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the status")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(resolvedImage))
+				Expect(operator.Status.InstalledBundleResource).To(Equal(hotfixImage))
+				Expect(operator.Status.InstalledBundleProvenance).To(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonOverridden))
+				Expect(cond.Message).To(Equal(fmt.Sprintf("resolved bundle %q is overridden by %q: installed from %q", resolvedImage, hotfixImage, hotfixImage)))
+
+				By("removing the override")
+				operator.Spec.BundleOverride = nil
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(resolvedImage))
+			})
+		})
+		When("a catalog update removes the installed operator's package", func() {
+			const installedImage = "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("keeps the installed bundle until the spec changes", func() {
+				By("installing the operator")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("running reconcile against catalog content without the package")
+				reconciler = &controllers.OperatorReconciler{
+					Client:   cl,
+					Scheme:   sch,
+					Resolver: solver.NewDeppySolver(input.NewCacheQuerier(map[deppy.Identifier]input.Entity{}), olm.NewOLMVariableSource(cl)),
+				}
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the installed bundle is kept")
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(installedImage))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseInstalled))
+				Expect(operator.Status.ResolvedBundleResource).To(Equal(installedImage))
+				Expect(operator.Status.InstalledBundleResource).To(Equal(installedImage))
+				Expect(operator.Status.InstalledBundleProvenance).NotTo(BeNil())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionRegressed))
+				Expect(cond.Message).To(Equal(fmt.Sprintf("package 'prometheus' not found; keeping installed bundle %q", installedImage)))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+				By("changing the operator's spec")
+				operator.Spec.Channel = "beta"
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).To(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+			})
+			It("reports a solver conflict with another operator as a resolution failure", func() {
+				const gvk = `[{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1"}]`
+				reconciler.Resolver = solver.NewDeppySolver(input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+					"operatorhub/prometheus/0.47.0": *input.NewEntity("operatorhub/prometheus/0.47.0", map[string]string{
+						"olm.bundle.path": fmt.Sprintf("%q", installedImage),
+						"olm.channel":     `{"channelName":"beta","priority":0}`,
+						"olm.package":     `{"packageName":"prometheus","version":"0.47.0"}`,
+						"olm.gvk":         gvk,
+					}),
+					"operatorhub/prometheus-fork/0.1.0": *input.NewEntity("operatorhub/prometheus-fork/0.1.0", map[string]string{
+						"olm.bundle.path": `"quay.io/operatorhubio/prometheus-fork@sha256:fork"`,
+						"olm.channel":     `{"channelName":"beta","priority":0}`,
+						"olm.package":     `{"packageName":"prometheus-fork","version":"0.1.0"}`,
+						"olm.gvk":         gvk,
+					}),
+				}), olm.NewOLMVariableSource(cl))
+
+				By("installing the operator")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("creating another operator for a package that provides the same API")
+				other := &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("operator-test-%s", rand.String(8))},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus-fork"},
+				}
+				Expect(cl.Create(ctx, other)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).To(HaveOccurred())
+
+				By("checking the failure is not hidden")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+				Expect(cond.Message).To(Equal(`entity for package "prometheus" not found in solution`))
+			})
+			It("reports another operator's missing package as a resolution failure", func() {
+				By("installing the operator")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("creating another operator for a package that doesn't exist")
+				other := &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("operator-test-%s", rand.String(8))},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "non-existent"},
+				}
+				Expect(cl.Create(ctx, other)).To(Succeed())
+
+				By("running reconcile")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).To(HaveOccurred())
+
+				By("checking the failure is not hidden")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+				Expect(cond.Message).To(Equal("package 'non-existent' not found"))
+			})
+		})
+		When("a repair is requested with the reconcile annotation", func() {
+			const tampered = "tampered"
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("re-applies the BundleDeployment once per request", func() {
+				tamper := func() {
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					bd.Annotations[controllers.AppliedAtAnnotation] = tampered
+					Expect(cl.Update(ctx, bd)).To(Succeed())
+				}
+				appliedAt := func() string {
+					bd := &rukpakv1alpha1.BundleDeployment{}
+					Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+					return bd.Annotations[controllers.AppliedAtAnnotation]
+				}
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				tamper()
+
+				By("checking an up to date BundleDeployment is not re-applied")
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(appliedAt()).To(Equal(tampered))
+
+				By("requesting a repair")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				operator.SetAnnotations(map[string]string{operatorsv1alpha1.ReconcileRequestAnnotation: "now"})
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(appliedAt()).NotTo(Equal(tampered))
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Status.LastHandledReconcileRequest).To(Equal("now"))
+
+				By("checking a handled request is not repeated")
+				tamper()
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(appliedAt()).To(Equal(tampered))
+			})
+		})
+		When("the operator has lifecycle hooks", func() {
+			const image = "quay.io/operatorhubio/prometheus@sha256:5b04c49d8d3eff6a338b56ec90bdf491d501fe301c9cdfb740e5bff6769a21ed"
+			hook := func(name string, phase operatorsv1alpha1.LifecycleHookPhase) operatorsv1alpha1.LifecycleHook {
+				return operatorsv1alpha1.LifecycleHook{
+					Name:  name,
+					Phase: phase,
+					Job: batchv1.JobSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								RestartPolicy: corev1.RestartPolicyNever,
+								Containers:    []corev1.Container{{Name: "hook", Image: "busybox"}},
+							},
+						},
+					},
+				}
+			}
+			finishJob := func(name string, condType batchv1.JobConditionType) {
+				job := &batchv1.Job{}
+				Expect(cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, job)).To(Succeed())
+				job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+					Type:    condType,
+					Status:  corev1.ConditionTrue,
+					Message: "job finished",
+				})
+				Expect(cl.Status().Update(ctx, job)).To(Succeed())
+			}
+			BeforeEach(func() {
+				cfg := config.Default()
+				cfg.LifecycleHooks.Namespace = "default"
+				reconciler.Config = config.NewStore(cfg)
+
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						LifecycleHooks: []operatorsv1alpha1.LifecycleHook{
+							hook("backup", operatorsv1alpha1.LifecycleHookPreInstall),
+							hook("smoke-test", operatorsv1alpha1.LifecycleHookPostInstall),
+						},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(cl.DeleteAllOf(ctx, &batchv1.Job{}, client.InNamespace("default"),
+						client.MatchingLabels{controllers.OperatorLabel: opKey.Name},
+						client.PropagationPolicy(metav1.DeletePropagationBackground))).To(Succeed())
+				})
+			})
+			It("runs the hooks before and after the bundle is installed", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the bundle is not applied while the PreInstall hook runs")
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(apierrors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd))).To(BeTrue())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForHooks))
+				Expect(cond.Message).To(Equal("waiting for lifecycle hooks: backup"))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseProgressing))
+				Expect(operator.Status.LifecycleHooks).To(HaveLen(1))
+				backup := operator.Status.LifecycleHooks[0]
+				Expect(backup.Bundle).To(Equal(image))
+				Expect(backup.State).To(Equal(operatorsv1alpha1.LifecycleHookRunning))
+
+				By("checking the hook's Job")
+				job := &batchv1.Job{}
+				Expect(cl.Get(ctx, types.NamespacedName{Namespace: "default", Name: backup.Job}, job)).To(Succeed())
+				Expect(metav1.IsControlledBy(job, operator)).To(BeTrue())
+				Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("busybox"))
+				Expect(job.Spec.Template.Spec.ServiceAccountName).To(BeEmpty())
+
+				By("running reconcile once the PreInstall hook completes")
+				finishJob(backup.Job, batchv1.JobComplete)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd)).To(Succeed())
+				Expect(bd.Spec.Template.Spec.Source.Image.Ref).To(Equal(image))
+
+				By("running reconcile once the bundle is installed")
+				markBundleDeploymentInstalled(ctx, bd)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonWaitingForHooks))
+				Expect(cond.Message).To(Equal("waiting for lifecycle hooks: smoke-test"))
+				Expect(operator.Status.LifecycleHooks).To(HaveLen(2))
+				smokeTest := operator.Status.LifecycleHooks[1]
+				Expect(smokeTest.Name).To(Equal("smoke-test"))
+
+				By("running reconcile once the PostInstall hook fails")
+				finishJob(smokeTest.Job, batchv1.JobFailed)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonHookFailed))
+				Expect(cond.Message).To(Equal("lifecycle hooks failed: smoke-test (job finished)"))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseFailed))
+
+				By("ignoring the failure")
+				operator.Spec.LifecycleHooks[1].FailurePolicy = operatorsv1alpha1.LifecycleHookFailurePolicyIgnore
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(operator.Status.LifecycleHooks[1].State).To(Equal(operatorsv1alpha1.LifecycleHookFailed))
+			})
+			It("fails hooks that ask for another service account without running them", func() {
+				By("asking for the controller's service account")
+				operator.Spec.LifecycleHooks[0].Job.Template.Spec.ServiceAccountName = "operator-controller-controller-manager"
+				Expect(cl.Update(ctx, operator)).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking no Job is created and the bundle is not applied")
+				jobs := &batchv1.JobList{}
+				Expect(cl.List(ctx, jobs, client.MatchingLabels{controllers.OperatorLabel: opKey.Name})).To(Succeed())
+				Expect(jobs.Items).To(BeEmpty())
+				bd := &rukpakv1alpha1.BundleDeployment{}
+				Expect(apierrors.IsNotFound(cl.Get(ctx, types.NamespacedName{Name: opKey.Name}, bd))).To(BeTrue())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonHookFailed))
+				Expect(cond.Message).To(Equal(`lifecycle hooks failed: backup (service account "operator-controller-controller-manager" is not allowed: lifecycle hooks run as "default")`))
+			})
+			It("fails hooks without running them while no hook namespace is configured", func() {
+				reconciler.Config = config.NewStore(config.Default())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking no Job is created")
+				jobs := &batchv1.JobList{}
+				Expect(cl.List(ctx, jobs, client.MatchingLabels{controllers.OperatorLabel: opKey.Name})).To(Succeed())
+				Expect(jobs.Items).To(BeEmpty())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonHookFailed))
+				Expect(cond.Message).To(Equal("lifecycle hooks failed: backup (no namespace is configured for lifecycle hooks)"))
+
+				By("running the hook once a namespace is configured")
+				cfg := config.Default()
+				cfg.LifecycleHooks.Namespace = "default"
+				reconciler.Config = config.NewStore(cfg)
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.List(ctx, jobs, client.MatchingLabels{controllers.OperatorLabel: opKey.Name})).To(Succeed())
+				Expect(jobs.Items).To(HaveLen(1))
+			})
+		})
+		When("the operator's deletion policy is Block", func() {
+			const holdFinalizer = "operators.operatorframework.io/test-hold"
+			var dependentKey types.NamespacedName
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: opKey.Name,
+						// Keeps the operator around once the reconciler releases it.
+						Finalizers: []string{holdFinalizer},
+					},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName:    "prometheus",
+						DeletionPolicy: operatorsv1alpha1.DeletionPolicyBlock,
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				DeferCleanup(func() {
+					op := &operatorsv1alpha1.Operator{}
+					if err := cl.Get(ctx, opKey, op); err == nil {
+						op.Finalizers = nil
+						Expect(cl.Update(ctx, op)).To(Succeed())
+					}
+				})
+				dependentKey = types.NamespacedName{Name: "dependent-" + opKey.Name}
+				Expect(cl.Create(ctx, &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: dependentKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "plain",
+						DependsOn:   []string{opKey.Name},
+					},
+				})).To(Succeed())
+
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).To(ContainElement(operatorsv1alpha1.DeletionProtectionFinalizer))
+			})
+			It("blocks deletion while another operator depends on it", func() {
+				By("deleting the operator")
+				Expect(cl.Delete(ctx, operator)).To(Succeed())
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeNumerically(">", 0))
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).To(ContainElement(operatorsv1alpha1.DeletionProtectionFinalizer))
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonDeletionBlocked))
+				Expect(cond.Message).To(Equal(fmt.Sprintf("deletion is blocked: operator %q depends on it", dependentKey.Name)))
+
+				By("deleting the dependent operator")
+				Expect(cl.Delete(ctx, &operatorsv1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: dependentKey.Name}})).To(Succeed())
+				res, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(Equal(ctrl.Result{}))
+
+				By("checking the operator was released")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).To(Equal([]string{holdFinalizer}))
+			})
+			It("blocks deletion while instances of an API it provides exist", func() {
+				By("recording a provided API that has instances")
+				api := &operatorsv1alpha1.ProvidedAPI{
+					ObjectMeta: metav1.ObjectMeta{Name: "catalog.catalogd.operatorframework.io"},
+					Spec:       operatorsv1alpha1.ProvidedAPISpec{Group: "catalogd.operatorframework.io", Kind: "Catalog"},
+				}
+				Expect(cl.Create(ctx, api)).To(Succeed())
+				api.Status.Providers = []operatorsv1alpha1.APIProvider{{Operator: opKey.Name, Versions: []string{"v1alpha1"}}}
+				Expect(cl.Status().Update(ctx, api)).To(Succeed())
+				createUnpackingCatalog(ctx, "deletion-test")
+				DeferCleanup(func() {
+					Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.ProvidedAPI{})).To(Succeed())
+					Expect(cl.DeleteAllOf(ctx, &catalogd.Catalog{})).To(Succeed())
+				})
+				Expect(cl.Delete(ctx, &operatorsv1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: dependentKey.Name}})).To(Succeed())
+
+				By("deleting the operator")
+				Expect(cl.Delete(ctx, operator)).To(Succeed())
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonDeletionBlocked))
+				Expect(cond.Message).To(Equal("deletion is blocked: instances of catalog.catalogd.operatorframework.io exist"))
+			})
+			It("blocks deletion while instances of an API it provides can't be listed", func() {
+				By("recording a provided API the reconciler has no access to")
+				api := &operatorsv1alpha1.ProvidedAPI{
+					ObjectMeta: metav1.ObjectMeta{Name: "catalog.catalogd.operatorframework.io"},
+					Spec:       operatorsv1alpha1.ProvidedAPISpec{Group: "catalogd.operatorframework.io", Kind: "Catalog"},
+				}
+				Expect(cl.Create(ctx, api)).To(Succeed())
+				api.Status.Providers = []operatorsv1alpha1.APIProvider{{Operator: opKey.Name, Versions: []string{"v1alpha1"}}}
+				Expect(cl.Status().Update(ctx, api)).To(Succeed())
+				DeferCleanup(func() {
+					Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.ProvidedAPI{})).To(Succeed())
+				})
+				Expect(cl.Delete(ctx, &operatorsv1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: dependentKey.Name}})).To(Succeed())
+				restricted := rest.CopyConfig(cfg)
+				restricted.Impersonate = rest.ImpersonationConfig{UserName: "deletion-protection-test"}
+				apiReader, err := client.New(restricted, client.Options{Scheme: sch})
+				Expect(err).NotTo(HaveOccurred())
+				reconciler.APIReader = apiReader
+
+				By("deleting the operator")
+				Expect(cl.Delete(ctx, operator)).To(Succeed())
+				_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				Expect(cl.Get(ctx, opKey, operator)).To(Succeed())
+				Expect(operator.Finalizers).To(ContainElement(operatorsv1alpha1.DeletionProtectionFinalizer))
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonDeletionBlocked))
+				Expect(cond.Message).To(Equal("deletion is blocked: instances of catalog.catalogd.operatorframework.io can't be listed without a ClusterRole labeled operators.operatorframework.io/aggregate-to-deletion-protection=true"))
+			})
+		})
+		When("the resolved bundle's catalog is unknown", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+			})
+			It("does not publish an upgrade graph", func() {
+				By("running reconcile")
+				_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+				Expect(operator.Status.UpgradeGraph).To(BeNil())
+			})
+		})
+		When("the operator requires a catalog that does not provide the package", func() {
+			BeforeEach(func() {
+				By("initializing cluster state")
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: "prometheus",
+						Catalog:     &operatorsv1alpha1.CatalogReference{Name: "operatorhub"},
+					},
+				}
+				Expect(cl.Create(ctx, operator)).To(Succeed())
+				createCatalogWithResolvedRef(ctx, "operatorhub", "quay.io/operatorhubio/catalog@sha256:snapshot")
+			})
+			AfterEach(func() {
+				Expect(cl.DeleteAllOf(ctx, &catalogd.Catalog{})).To(Succeed())
+			})
+			It("sets resolution failure status", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).To(MatchError("package 'prometheus' in catalog 'operatorhub' not found"))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonResolutionFailed))
+				Expect(cond.Message).To(Equal("package 'prometheus' in catalog 'operatorhub' not found"))
+			})
+		})
+		When("the operator specifies a package with a bad bundle mediatype", func() {
+			var pkgName string
+			var pkgVer string
+			var pkgChan string
+			BeforeEach(func() {
+				By("initializing cluster state")
+				pkgName = "badmedia"
+				pkgVer = "0.1.0"
+				pkgChan = "beta"
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: pkgName,
+						Version:     pkgVer,
+						Channel:     pkgChan,
+					},
+				}
+				err := cl.Create(ctx, operator)
+				Expect(err).NotTo(HaveOccurred())
+			})
+			It("sets resolution success status", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown bundle mediatype: badmedia+v1"))
+
+				By("fetching updated operator after reconcile")
+				Expect(cl.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
+
+				By("Checking the status fields")
+				Expect(operator.Status.ResolvedBundleResource).To(Equal("quay.io/operatorhub/badmedia@sha256:badmedia"))
+				Expect(operator.Status.InstalledBundleResource).To(Equal(""))
+
+				By("checking the expected conditions")
+				cond := apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeResolved)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
+				Expect(cond.Message).To(Equal("resolved to \"quay.io/operatorhub/badmedia@sha256:badmedia\""))
+				cond = apimeta.FindStatusCondition(operator.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+				Expect(cond).NotTo(BeNil())
+				Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationFailed))
+				Expect(cond.Message).To(Equal("unknown bundle mediatype: badmedia+v1"))
+			})
+		})
+		When("an invalid semver is provided that bypasses the regex validation", func() {
+			var (
+				pkgName    string
+				fakeClient client.Client
+			)
+			BeforeEach(func() {
+				opKey = types.NamespacedName{Name: fmt.Sprintf("operator-validation-test-%s", rand.String(8))}
+
+				By("injecting creating a client with the bad operator CR")
+				pkgName = fmt.Sprintf("exists-%s", rand.String(6))
+				operator = &operatorsv1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: opKey.Name},
+					Spec: operatorsv1alpha1.OperatorSpec{
+						PackageName: pkgName,
+						Version:     "1.2.3-123abc_def", // bad semver that matches the regex on the CR validation
+					},
+				}
+
+				// this bypasses client/server-side CR validation and allows us to test the reconciler's validation
+				fakeClient = fake.NewClientBuilder().WithScheme(sch).WithObjects(operator).Build()
+
+				By("changing the reconciler client to the fake client")
+				reconciler.Client = fakeClient
+			})
+
+			It("should add an invalid spec condition and *not* re-enqueue for reconciliation", func() {
+				By("running reconcile")
+				res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: opKey})
+				Expect(res).To(Equal(ctrl.Result{}))
+				Expect(err).ToNot(HaveOccurred())
 
 				By("fetching updated operator after reconcile")
 				Expect(fakeClient.Get(ctx, opKey, operator)).NotTo(HaveOccurred())
@@ -1054,17 +2959,100 @@ var _ = Describe("Operator Controller Test", func() {
 				Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
 				Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
 				Expect(cond.Message).To(Equal("installation has not been attempted as spec is invalid"))
+				Expect(operator.Status.Phase).To(Equal(operatorsv1alpha1.PhaseFailed))
 			})
 		})
 	})
 })
 
+func createDependencyOperator(ctx context.Context, name string, installed metav1.ConditionStatus) {
+	dep := &operatorsv1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "plain"},
+	}
+	Expect(cl.Create(ctx, dep)).To(Succeed())
+	apimeta.SetStatusCondition(&dep.Status.Conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             installed,
+		Reason:             operatorsv1alpha1.ReasonSuccess,
+		Message:            "installed",
+		ObservedGeneration: dep.GetGeneration(),
+	})
+	Expect(cl.Status().Update(ctx, dep)).To(Succeed())
+}
+
+// markBundleDeploymentInstalled sets the Installed condition of bd to True for
+// its current generation.
+func markBundleDeploymentInstalled(ctx context.Context, bd *rukpakv1alpha1.BundleDeployment) {
+	bd.Status.ObservedGeneration = bd.GetGeneration()
+	apimeta.SetStatusCondition(&bd.Status.Conditions, metav1.Condition{
+		Type:    rukpakv1alpha1.TypeInstalled,
+		Status:  metav1.ConditionTrue,
+		Reason:  rukpakv1alpha1.ReasonInstallationSucceeded,
+		Message: "installed",
+	})
+	Expect(cl.Status().Update(ctx, bd)).To(Succeed())
+}
+
+func createBundleMetadata(ctx context.Context, name, pkg, image string, properties ...catalogd.Property) {
+	Expect(cl.Create(ctx, &catalogd.BundleMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: catalogd.BundleMetadataSpec{
+			Catalog:    corev1.LocalObjectReference{Name: "operatorhub"},
+			Package:    pkg,
+			Image:      image,
+			Properties: properties,
+		},
+	})).To(Succeed())
+}
+
+func createCatalogWithResolvedRef(ctx context.Context, name, ref string) {
+	catalog := &catalogd.Catalog{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: catalogd.CatalogSpec{
+			Source: catalogd.CatalogSource{
+				Type:  catalogd.SourceTypeImage,
+				Image: &catalogd.ImageSource{Ref: "quay.io/operatorhubio/catalog:latest"},
+			},
+		},
+	}
+	Expect(cl.Create(ctx, catalog)).To(Succeed())
+	catalog.Status.Phase = catalogd.PhaseUnpacked
+	catalog.Status.ResolvedSource = &catalogd.CatalogSource{
+		Type:  catalogd.SourceTypeImage,
+		Image: &catalogd.ImageSource{Ref: ref},
+	}
+	Expect(cl.Status().Update(ctx, catalog)).To(Succeed())
+}
+
+func createUnpackingCatalog(ctx context.Context, name string) {
+	catalog := &catalogd.Catalog{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: catalogd.CatalogSpec{
+			Source: catalogd.CatalogSource{
+				Type:  catalogd.SourceTypeImage,
+				Image: &catalogd.ImageSource{Ref: "quay.io/operatorhubio/catalog:latest"},
+			},
+		},
+	}
+	Expect(cl.Create(ctx, catalog)).To(Succeed())
+	catalog.Status.Phase = catalogd.PhaseUnpacking
+	apimeta.SetStatusCondition(&catalog.Status.Conditions, metav1.Condition{
+		Type:    catalogd.TypeUnpacked,
+		Status:  metav1.ConditionFalse,
+		Reason:  catalogd.ReasonUnpacking,
+		Message: "unpacking catalog image",
+	})
+	Expect(cl.Status().Update(ctx, catalog)).To(Succeed())
+}
+
 func verifyInvariants(ctx context.Context, c client.Client, op *operatorsv1alpha1.Operator) {
 	key := client.ObjectKeyFromObject(op)
 	err := c.Get(ctx, key, op)
 	Expect(err).To(BeNil())
 
 	verifyConditionsInvariants(op)
+	verifyPhaseInvariants(op)
 }
 
 func verifyConditionsInvariants(op *operatorsv1alpha1.Operator) {
@@ -1082,6 +3070,43 @@ func verifyConditionsInvariants(op *operatorsv1alpha1.Operator) {
 	}
 }
 
+func verifyPhaseInvariants(op *operatorsv1alpha1.Operator) {
+	// Expect that the phase is consistent with the Installed condition.
+	installed := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+	Expect(installed).NotTo(BeNil())
+	if installed.Status == metav1.ConditionTrue {
+		Expect(op.Status.Phase).To(Equal(operatorsv1alpha1.PhaseInstalled))
+	} else {
+		Expect(op.Status.Phase).To(BeElementOf(operatorsv1alpha1.PhaseProgressing, operatorsv1alpha1.PhaseFailed))
+	}
+}
+
+// countingEntitySource counts the queries made against an entity source.
+type countingEntitySource struct {
+	input.EntitySource
+	calls int
+}
+
+func (c *countingEntitySource) Get(ctx context.Context, id deppy.Identifier) (*input.Entity, error) {
+	c.calls++
+	return c.EntitySource.Get(ctx, id)
+}
+
+func (c *countingEntitySource) Filter(ctx context.Context, filter input.Predicate) (input.EntityList, error) {
+	c.calls++
+	return c.EntitySource.Filter(ctx, filter)
+}
+
+func (c *countingEntitySource) GroupBy(ctx context.Context, fn input.GroupByFunction) (input.EntityListMap, error) {
+	c.calls++
+	return c.EntitySource.GroupBy(ctx, fn)
+}
+
+func (c *countingEntitySource) Iterate(ctx context.Context, fn input.IteratorFunction) error {
+	c.calls++
+	return c.EntitySource.Iterate(ctx, fn)
+}
+
 var testEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
 	"operatorhub/prometheus/0.37.0": *input.NewEntity("operatorhub/prometheus/0.37.0", map[string]string{
 		"olm.bundle.path": `"quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"`,
@@ -1106,6 +3131,8 @@ var testEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
 		"olm.package":          `{"packageName":"plain","version":"0.1.0"}`,
 		"olm.gvk":              `[]`,
 		"olm.bundle.mediatype": `"plain+v0"`,
+		"olm.bundle.catalog":   `{"name":"operatorhub","ref":"quay.io/operatorhubio/catalog@sha256:snapshot"}`,
+		"olm.bundle.name":      `"plain.v0.1.0"`,
 	}),
 	"operatorhub/badmedia/0.1.0": *input.NewEntity("operatorhub/badmedia/0.1.0", map[string]string{
 		"olm.bundle.path":      `"quay.io/operatorhub/badmedia@sha256:badmedia"`,
@@ -0,0 +1,276 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/config"
+)
+
+// OperatorSetReconciler reconciles an OperatorSet object
+type OperatorSetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Config provides the reloadable controller configuration. If nil,
+	// config.Default() is used.
+	Config *config.Store
+}
+
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operatorsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operatorsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operatorsets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=operators,verbs=create;delete;patch
+
+func (r *OperatorSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx).WithName("operatorset-controller")
+	l.V(1).Info("starting")
+	defer l.V(1).Info("ending")
+
+	existingSet := &operatorsv1alpha1.OperatorSet{}
+	if err := r.Get(ctx, req.NamespacedName, existingSet); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	reconciledSet := existingSet.DeepCopy()
+	res, reconcileErr := r.reconcile(ctx, reconciledSet)
+
+	if !equality.Semantic.DeepEqual(existingSet.Status, reconciledSet.Status) {
+		if updateErr := r.Status().Update(ctx, reconciledSet); updateErr != nil {
+			return res, utilerrors.NewAggregate([]error{reconcileErr, updateErr})
+		}
+	}
+	return res, reconcileErr
+}
+
+func (r *OperatorSetReconciler) reconcile(ctx context.Context, set *operatorsv1alpha1.OperatorSet) (ctrl.Result, error) {
+	desired := map[string]struct{}{}
+	members := make([]operatorsv1alpha1.OperatorSetMemberStatus, 0, len(set.Spec.Packages))
+	var conflicts []string
+	for _, pkg := range set.Spec.Packages {
+		op := r.generateExpectedOperator(*set, pkg)
+		desired[op.GetName()] = struct{}{}
+		// An Operator with the member's name that this set did not create is
+		// left alone, so that it is neither taken over nor deleted later.
+		existingOp := &operatorsv1alpha1.Operator{}
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(op), existingOp); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		} else if err == nil && !metav1.IsControlledBy(existingOp, set) {
+			conflicts = append(conflicts, op.GetName())
+			continue
+		}
+		if err := r.Client.Patch(ctx, op, client.Apply, client.FieldOwner(r.Config.Get().FieldManager), client.ForceOwnership); err != nil {
+			setOperatorSetInstalledCondition(set, metav1.ConditionFalse, operatorsv1alpha1.ReasonInstallationFailed,
+				fmt.Sprintf("applying operator %q: %v", op.GetName(), err))
+			return ctrl.Result{}, err
+		}
+		typedOp := &operatorsv1alpha1.Operator{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(op.UnstructuredContent(), typedOp); err != nil {
+			return ctrl.Result{}, err
+		}
+		members = append(members, memberStatus(typedOp))
+	}
+
+	// Delete Operators for packages that were removed from the set.
+	existing := &operatorsv1alpha1.OperatorList{}
+	if err := r.Client.List(ctx, existing, client.MatchingLabels{operatorsv1alpha1.OperatorSetLabel: set.GetName()}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		op := &existing.Items[i]
+		if _, ok := desired[op.GetName()]; ok || !metav1.IsControlledBy(op, set) {
+			continue
+		}
+		if err := r.Client.Delete(ctx, op); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	set.Status.Operators = members
+	setOperatorSetInstalledConditionFromMembers(set)
+	if len(conflicts) > 0 {
+		setOperatorSetInstalledCondition(set, metav1.ConditionFalse, operatorsv1alpha1.ReasonOwnershipConflict,
+			fmt.Sprintf("operators not created by this OperatorSet already exist: %s", strings.Join(conflicts, ", ")))
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *OperatorSetReconciler) generateExpectedOperator(set operatorsv1alpha1.OperatorSet, pkg operatorsv1alpha1.OperatorSetPackage) *unstructured.Unstructured {
+	// See generateExpectedBundleDeployment for why unstructured is used here.
+	spec := map[string]interface{}{
+		"packageName": pkg.PackageName,
+	}
+	if pkg.Version != "" {
+		spec["version"] = pkg.Version
+	}
+	channel := pkg.Channel
+	if channel == "" {
+		channel = set.Spec.Template.Channel
+	}
+	if channel != "" {
+		spec["channel"] = channel
+	}
+	if c := set.Spec.Template.Catalog; c != nil {
+		catalog := map[string]interface{}{"name": c.Name}
+		if c.Ref != "" {
+			catalog["ref"] = c.Ref
+		}
+		spec["catalog"] = catalog
+	}
+
+	op := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": operatorsv1alpha1.GroupVersion.String(),
+		"kind":       "Operator",
+		"metadata": map[string]interface{}{
+			"name": operatorSetMemberName(set.GetName(), pkg.PackageName),
+		},
+		"spec": spec,
+	}}
+	labels := map[string]string{}
+	for k, v := range set.Spec.Template.Labels {
+		labels[k] = v
+	}
+	labels[operatorsv1alpha1.OperatorSetLabel] = set.GetName()
+	op.SetLabels(labels)
+	if len(set.Spec.Template.Annotations) > 0 {
+		op.SetAnnotations(set.Spec.Template.Annotations)
+	}
+	op.SetOwnerReferences([]metav1.OwnerReference{
+		{
+			APIVersion:         operatorsv1alpha1.GroupVersion.String(),
+			Kind:               "OperatorSet",
+			Name:               set.GetName(),
+			UID:                set.GetUID(),
+			Controller:         pointer.Bool(true),
+			BlockOwnerDeletion: pointer.Bool(true),
+		},
+	})
+	return op
+}
+
+// operatorSetMemberName returns the name of the Operator an OperatorSet
+// creates for a package.
+func operatorSetMemberName(setName, packageName string) string {
+	return fmt.Sprintf("%s-%s", setName, packageName)
+}
+
+func memberStatus(op *operatorsv1alpha1.Operator) operatorsv1alpha1.OperatorSetMemberStatus {
+	installed := metav1.ConditionUnknown
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); cond != nil && cond.ObservedGeneration == op.GetGeneration() {
+		installed = cond.Status
+	}
+	return operatorsv1alpha1.OperatorSetMemberStatus{
+		Name:                    op.GetName(),
+		PackageName:             op.Spec.PackageName,
+		InstalledBundleResource: op.Status.InstalledBundleResource,
+		Installed:               installed,
+	}
+}
+
+// setOperatorSetInstalledConditionFromMembers aggregates the Installed
+// conditions of the set's Operators: it is True once every Operator is
+// installed, False if any Operator failed to install and Unknown otherwise.
+func setOperatorSetInstalledConditionFromMembers(set *operatorsv1alpha1.OperatorSet) {
+	var failed, pending []string
+	for _, m := range set.Status.Operators {
+		switch m.Installed {
+		case metav1.ConditionTrue:
+		case metav1.ConditionFalse:
+			failed = append(failed, m.Name)
+		default:
+			pending = append(pending, m.Name)
+		}
+	}
+	installed := len(set.Status.Operators) - len(failed) - len(pending)
+	msg := fmt.Sprintf("%d/%d operators installed", installed, len(set.Status.Operators))
+	switch {
+	case len(failed) > 0:
+		setOperatorSetInstalledCondition(set, metav1.ConditionFalse, operatorsv1alpha1.ReasonInstallationFailed,
+			fmt.Sprintf("%s; not installed: %s", msg, strings.Join(append(failed, pending...), ", ")))
+	case len(pending) > 0:
+		setOperatorSetInstalledCondition(set, metav1.ConditionUnknown, operatorsv1alpha1.ReasonInstallationStatusUnknown,
+			fmt.Sprintf("%s; waiting for: %s", msg, strings.Join(pending, ", ")))
+	default:
+		setOperatorSetInstalledCondition(set, metav1.ConditionTrue, operatorsv1alpha1.ReasonSuccess, msg)
+	}
+}
+
+func setOperatorSetInstalledCondition(set *operatorsv1alpha1.OperatorSet, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&set.Status.Conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: set.GetGeneration(),
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&operatorsv1alpha1.OperatorSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// Operators are mapped to OperatorSets by name rather than by owner, so
+		// that sets with a conflicting Operator are reconciled again once it
+		// changes or is deleted.
+		Watches(&source.Kind{Type: &operatorsv1alpha1.Operator{}},
+			handler.EnqueueRequestsFromMapFunc(operatorSetRequestsForOperator(context.TODO(), mgr.GetClient(), mgr.GetLogger()))).
+		Complete(r)
+}
+
+// operatorSetRequestsForOperator generates reconcile requests for the
+// OperatorSets that have a member named after the changed Operator.
+func operatorSetRequestsForOperator(ctx context.Context, c client.Reader, logger logr.Logger) handler.MapFunc {
+	return func(object client.Object) []reconcile.Request {
+		sets := &operatorsv1alpha1.OperatorSetList{}
+		if err := c.List(ctx, sets); err != nil {
+			logger.Error(err, "unable to enqueue operator sets for operator")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, set := range sets.Items {
+			for _, pkg := range set.Spec.Packages {
+				if operatorSetMemberName(set.GetName(), pkg.PackageName) == object.GetName() {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: set.GetName()}})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
@@ -0,0 +1,186 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/controllers"
+)
+
+var _ = Describe("OperatorSet Controller Test", func() {
+	var (
+		ctx        context.Context
+		reconciler *controllers.OperatorSetReconciler
+		set        *operatorsv1alpha1.OperatorSet
+		setKey     types.NamespacedName
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &controllers.OperatorSetReconciler{
+			Client: cl,
+			Scheme: sch,
+		}
+		setKey = types.NamespacedName{Name: fmt.Sprintf("operatorset-test-%s", rand.String(8))}
+		set = &operatorsv1alpha1.OperatorSet{
+			ObjectMeta: metav1.ObjectMeta{Name: setKey.Name},
+			Spec: operatorsv1alpha1.OperatorSetSpec{
+				Template: operatorsv1alpha1.OperatorSetTemplate{
+					Labels:  map[string]string{"team": "platform"},
+					Channel: "beta",
+				},
+				Packages: []operatorsv1alpha1.OperatorSetPackage{
+					{PackageName: "prometheus", Version: "0.47.0"},
+					{PackageName: "plain", Channel: "stable"},
+				},
+			},
+		}
+		Expect(cl.Create(ctx, set)).To(Succeed())
+	})
+	AfterEach(func() {
+		Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.OperatorSet{})).To(Succeed())
+		Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.Operator{})).To(Succeed())
+	})
+
+	It("returns no error when the operator set does not exist", func() {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "non-existent"}})
+		Expect(res).To(Equal(ctrl.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates an Operator for every package from the template", func() {
+		By("running reconcile")
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(res).To(Equal(ctrl.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+
+		By("checking the created operators")
+		op := &operatorsv1alpha1.Operator{}
+		Expect(cl.Get(ctx, types.NamespacedName{Name: setKey.Name + "-prometheus"}, op)).To(Succeed())
+		Expect(op.Spec.PackageName).To(Equal("prometheus"))
+		Expect(op.Spec.Version).To(Equal("0.47.0"))
+		Expect(op.Spec.Channel).To(Equal("beta"))
+		Expect(op.Labels).To(HaveKeyWithValue("team", "platform"))
+		Expect(op.Labels).To(HaveKeyWithValue(operatorsv1alpha1.OperatorSetLabel, setKey.Name))
+		Expect(metav1.IsControlledBy(op, set)).To(BeTrue())
+
+		Expect(cl.Get(ctx, types.NamespacedName{Name: setKey.Name + "-plain"}, op)).To(Succeed())
+		Expect(op.Spec.PackageName).To(Equal("plain"))
+		Expect(op.Spec.Channel).To(Equal("stable"))
+
+		By("checking the operator set status")
+		Expect(cl.Get(ctx, setKey, set)).To(Succeed())
+		Expect(set.Status.Operators).To(HaveLen(2))
+		Expect(set.Status.Operators[0].Name).To(Equal(setKey.Name + "-prometheus"))
+		Expect(set.Status.Operators[0].Installed).To(Equal(metav1.ConditionUnknown))
+		cond := apimeta.FindStatusCondition(set.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonInstallationStatusUnknown))
+		Expect(cond.Message).To(Equal(fmt.Sprintf("0/2 operators installed; waiting for: %[1]s-prometheus, %[1]s-plain", setKey.Name)))
+	})
+
+	It("reports the set installed once every operator is installed", func() {
+		By("running reconcile")
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("marking the operators installed")
+		for _, name := range []string{setKey.Name + "-prometheus", setKey.Name + "-plain"} {
+			op := &operatorsv1alpha1.Operator{}
+			Expect(cl.Get(ctx, types.NamespacedName{Name: name}, op)).To(Succeed())
+			op.Status.InstalledBundleResource = "quay.io/operatorhubio/" + op.Spec.PackageName + "@sha256:abc"
+			apimeta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+				Type:               operatorsv1alpha1.TypeInstalled,
+				Status:             metav1.ConditionTrue,
+				Reason:             operatorsv1alpha1.ReasonSuccess,
+				Message:            "installed",
+				ObservedGeneration: op.GetGeneration(),
+			})
+			Expect(cl.Status().Update(ctx, op)).To(Succeed())
+		}
+
+		By("running reconcile again")
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("checking the operator set status")
+		Expect(cl.Get(ctx, setKey, set)).To(Succeed())
+		Expect(set.Status.Operators[1].InstalledBundleResource).To(Equal("quay.io/operatorhubio/plain@sha256:abc"))
+		cond := apimeta.FindStatusCondition(set.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonSuccess))
+		Expect(cond.Message).To(Equal("2/2 operators installed"))
+	})
+
+	It("deletes operators for packages removed from the set", func() {
+		By("running reconcile")
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("removing a package")
+		Expect(cl.Get(ctx, setKey, set)).To(Succeed())
+		set.Spec.Packages = set.Spec.Packages[:1]
+		Expect(cl.Update(ctx, set)).To(Succeed())
+
+		By("running reconcile again")
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("checking the removed package's operator is gone")
+		err = cl.Get(ctx, types.NamespacedName{Name: setKey.Name + "-plain"}, &operatorsv1alpha1.Operator{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(cl.Get(ctx, types.NamespacedName{Name: setKey.Name + "-prometheus"}, &operatorsv1alpha1.Operator{})).To(Succeed())
+
+		Expect(cl.Get(ctx, setKey, set)).To(Succeed())
+		Expect(set.Status.Operators).To(HaveLen(1))
+	})
+
+	It("leaves operators it did not create alone", func() {
+		By("creating an operator with a member's name")
+		userOp := &operatorsv1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: setKey.Name + "-plain"},
+			Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "plain", Channel: "beta"},
+		}
+		Expect(cl.Create(ctx, userOp)).To(Succeed())
+
+		By("running reconcile")
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("checking the operator is unchanged")
+		op := &operatorsv1alpha1.Operator{}
+		Expect(cl.Get(ctx, types.NamespacedName{Name: userOp.Name}, op)).To(Succeed())
+		Expect(op.Spec.Channel).To(Equal("beta"))
+		Expect(op.OwnerReferences).To(BeEmpty())
+		Expect(op.Labels).NotTo(HaveKey(operatorsv1alpha1.OperatorSetLabel))
+
+		By("checking the conflict is reported")
+		Expect(cl.Get(ctx, setKey, set)).To(Succeed())
+		Expect(set.Status.Operators).To(HaveLen(1))
+		cond := apimeta.FindStatusCondition(set.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+		Expect(cond.Reason).To(Equal(operatorsv1alpha1.ReasonOwnershipConflict))
+		Expect(cond.Message).To(Equal(fmt.Sprintf("operators not created by this OperatorSet already exist: %s", userOp.Name)))
+
+		By("removing the package from the set")
+		set.Spec.Packages = set.Spec.Packages[:1]
+		Expect(cl.Update(ctx, set)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: setKey})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("checking the operator is not deleted")
+		Expect(cl.Get(ctx, types.NamespacedName{Name: userOp.Name}, op)).To(Succeed())
+	})
+})
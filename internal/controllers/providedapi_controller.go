@@ -0,0 +1,229 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// ProvidedAPIReconciler maintains a ProvidedAPI for every API provided by the
+// bundle of an installed Operator.
+type ProvidedAPIReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// providedAPIIndexRequest is the only request handled by the
+// ProvidedAPIReconciler. The index is small and its entries depend on every
+// installed Operator, so it is always rebuilt as a whole.
+var providedAPIIndexRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "provided-apis"}}
+
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=providedapis,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=operators.operatorframework.io,resources=providedapis/status,verbs=get;update;patch
+
+func (r *ProvidedAPIReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	l := log.FromContext(ctx).WithName("providedapi-controller")
+	l.V(1).Info("starting")
+	defer l.V(1).Info("ending")
+
+	desired, err := r.desiredProvidedAPIs(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	existing := &operatorsv1alpha1.ProvidedAPIList{}
+	if err := r.Client.List(ctx, existing); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range existing.Items {
+		api := &existing.Items[i]
+		want, ok := desired[api.GetName()]
+		if !ok {
+			if err := r.Client.Delete(ctx, api); client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		delete(desired, api.GetName())
+		if equality.Semantic.DeepEqual(api.Status, want.Status) {
+			continue
+		}
+		api.Status = want.Status
+		if err := r.Client.Status().Update(ctx, api); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, want := range desired {
+		status := want.Status
+		if err := r.Client.Create(ctx, want); err != nil {
+			return ctrl.Result{}, err
+		}
+		want.Status = status
+		if err := r.Client.Status().Update(ctx, want); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// desiredProvidedAPIs returns the ProvidedAPIs for the bundles of all
+// installed Operators, keyed by name.
+func (r *ProvidedAPIReconciler) desiredProvidedAPIs(ctx context.Context) (map[string]*operatorsv1alpha1.ProvidedAPI, error) {
+	bundles, err := installedBundles(ctx, r.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	apis := map[string]*operatorsv1alpha1.ProvidedAPI{}
+	for opName, bundle := range bundles {
+		gvks, err := providedGVKs(bundle.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("bundle %q of operator %q: %w", bundle.Resource, opName, err)
+		}
+		for _, gvk := range gvks {
+			name := operatorsv1alpha1.ProvidedAPIName(gvk.Group, gvk.Kind)
+			api, ok := apis[name]
+			if !ok {
+				api = &operatorsv1alpha1.ProvidedAPI{Spec: operatorsv1alpha1.ProvidedAPISpec{Group: gvk.Group, Kind: gvk.Kind}}
+				api.SetName(name)
+				apis[name] = api
+			}
+			addAPIProvider(api, opName, bundle.Resource, gvk.Version)
+		}
+	}
+
+	for _, api := range apis {
+		sort.Slice(api.Status.Providers, func(i, j int) bool {
+			return api.Status.Providers[i].Operator < api.Status.Providers[j].Operator
+		})
+		for i := range api.Status.Providers {
+			sort.Strings(api.Status.Providers[i].Versions)
+		}
+	}
+	return apis, nil
+}
+
+func addAPIProvider(api *operatorsv1alpha1.ProvidedAPI, opName, bundleResource, version string) {
+	for i := range api.Status.Providers {
+		p := &api.Status.Providers[i]
+		if p.Operator != opName {
+			continue
+		}
+		for _, v := range p.Versions {
+			if v == version {
+				return
+			}
+		}
+		p.Versions = append(p.Versions, version)
+		return
+	}
+	api.Status.Providers = append(api.Status.Providers, operatorsv1alpha1.APIProvider{
+		Operator:                opName,
+		InstalledBundleResource: bundleResource,
+		Versions:                []string{version},
+	})
+}
+
+// installedBundle is the bundle installed by an Operator.
+type installedBundle struct {
+	// Resource is the bundle image reported by the Operator.
+	Resource string
+	// Properties are the catalog properties of the bundle.
+	Properties []catalogd.Property
+}
+
+// installedBundles returns the bundles of all installed Operators that can be
+// found in a catalog, keyed by Operator name.
+func installedBundles(ctx context.Context, c client.Reader) (map[string]installedBundle, error) {
+	operators := &operatorsv1alpha1.OperatorList{}
+	if err := c.List(ctx, operators); err != nil {
+		return nil, err
+	}
+	byImage := map[string][]string{}
+	for _, op := range operators.Items {
+		if op.Status.InstalledBundleResource == "" || !apimeta.IsStatusConditionTrue(op.Status.Conditions, operatorsv1alpha1.TypeInstalled) {
+			continue
+		}
+		byImage[op.Status.InstalledBundleResource] = append(byImage[op.Status.InstalledBundleResource], op.GetName())
+	}
+	if len(byImage) == 0 {
+		return nil, nil
+	}
+
+	bundleMetadatas := &catalogd.BundleMetadataList{}
+	if err := c.List(ctx, bundleMetadatas); err != nil {
+		return nil, err
+	}
+	bundles := map[string]installedBundle{}
+	for _, bm := range bundleMetadatas.Items {
+		for _, opName := range byImage[bm.Spec.Image] {
+			// The same image may be listed by several catalogs; its
+			// properties are the same in all of them.
+			if _, ok := bundles[opName]; !ok {
+				bundles[opName] = installedBundle{Resource: bm.Spec.Image, Properties: bm.Spec.Properties}
+			}
+		}
+	}
+	return bundles, nil
+}
+
+// providedGVKs returns the APIs declared by the olm.gvk properties.
+func providedGVKs(properties []catalogd.Property) ([]property.GVK, error) {
+	var gvks []property.GVK
+	for _, prop := range properties {
+		if prop.Type != property.TypeGVK {
+			continue
+		}
+		var gvk property.GVK
+		if err := json.Unmarshal(prop.Value, &gvk); err != nil {
+			return nil, fmt.Errorf("property '%s' ('%s') could not be parsed: %w", prop.Type, prop.Value, err)
+		}
+		gvks = append(gvks, gvk)
+	}
+	return gvks, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ProvidedAPIReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toIndex := handler.EnqueueRequestsFromMapFunc(func(client.Object) []reconcile.Request {
+		return []reconcile.Request{providedAPIIndexRequest}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("providedapi").
+		Watches(&source.Kind{Type: &operatorsv1alpha1.Operator{}}, toIndex).
+		Watches(&source.Kind{Type: &operatorsv1alpha1.ProvidedAPI{}}, toIndex).
+		Complete(r)
+}
@@ -0,0 +1,102 @@
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/controllers"
+)
+
+var _ = Describe("ProvidedAPI Controller Test", func() {
+	const prometheusImage = "quay.io/operatorhubio/prometheus@sha256:installed"
+	var (
+		ctx        context.Context
+		reconciler *controllers.ProvidedAPIReconciler
+	)
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &controllers.ProvidedAPIReconciler{
+			Client: cl,
+			Scheme: sch,
+		}
+		createBundleMetadata(ctx, "operatorhub-prometheus.v0.47.0", "prometheus", prometheusImage,
+			catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"0.47.0"}`)},
+			catalogd.Property{Type: "olm.gvk", Value: []byte(`{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1"}`)},
+			catalogd.Property{Type: "olm.gvk", Value: []byte(`{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1alpha1"}`)},
+			catalogd.Property{Type: "olm.gvk", Value: []byte(`{"group":"monitoring.coreos.com","kind":"Alertmanager","version":"v1"}`)},
+		)
+	})
+	AfterEach(func() {
+		Expect(cl.DeleteAllOf(ctx, &catalogd.BundleMetadata{})).To(Succeed())
+		Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.Operator{})).To(Succeed())
+		Expect(cl.DeleteAllOf(ctx, &operatorsv1alpha1.ProvidedAPI{})).To(Succeed())
+	})
+
+	reconcileIndex := func() {
+		res, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "provided-apis"}})
+		Expect(res).To(Equal(ctrl.Result{}))
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("records the APIs provided by installed operators and removes them once uninstalled", func() {
+		By("installing an operator")
+		op := &operatorsv1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus"},
+			Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+		}
+		Expect(cl.Create(ctx, op)).To(Succeed())
+		op.Status.InstalledBundleResource = prometheusImage
+		apimeta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+			Type:               operatorsv1alpha1.TypeInstalled,
+			Status:             metav1.ConditionTrue,
+			Reason:             operatorsv1alpha1.ReasonSuccess,
+			Message:            "installed",
+			ObservedGeneration: op.GetGeneration(),
+		})
+		Expect(cl.Status().Update(ctx, op)).To(Succeed())
+
+		By("running reconcile")
+		reconcileIndex()
+
+		By("checking the provided APIs")
+		api := &operatorsv1alpha1.ProvidedAPI{}
+		Expect(cl.Get(ctx, types.NamespacedName{Name: "prometheus.monitoring.coreos.com"}, api)).To(Succeed())
+		Expect(api.Spec).To(Equal(operatorsv1alpha1.ProvidedAPISpec{Group: "monitoring.coreos.com", Kind: "Prometheus"}))
+		Expect(api.Status.Providers).To(Equal([]operatorsv1alpha1.APIProvider{
+			{Operator: "prometheus", InstalledBundleResource: prometheusImage, Versions: []string{"v1", "v1alpha1"}},
+		}))
+		Expect(cl.Get(ctx, types.NamespacedName{Name: "alertmanager.monitoring.coreos.com"}, api)).To(Succeed())
+		Expect(api.Status.Providers).To(HaveLen(1))
+
+		By("uninstalling the operator")
+		Expect(cl.Delete(ctx, op)).To(Succeed())
+		reconcileIndex()
+
+		By("checking the provided APIs were removed")
+		err := cl.Get(ctx, types.NamespacedName{Name: "prometheus.monitoring.coreos.com"}, api)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("ignores operators that are not installed", func() {
+		op := &operatorsv1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "prometheus"},
+			Spec:       operatorsv1alpha1.OperatorSpec{PackageName: "prometheus"},
+		}
+		Expect(cl.Create(ctx, op)).To(Succeed())
+
+		reconcileIndex()
+
+		apis := &operatorsv1alpha1.ProvidedAPIList{}
+		Expect(cl.List(ctx, apis)).To(Succeed())
+		Expect(apis.Items).To(BeEmpty())
+	})
+})
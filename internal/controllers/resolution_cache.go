@@ -0,0 +1,166 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+	"github.com/operator-framework/deppy/pkg/deppy/solver"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// resolutionCache holds the last successful solution along with the inputs it
+// was computed from. Resolution is global, so a solution can be reused by every
+// Operator as long as no Operator's resolution inputs, no Catalog and none of
+// the catalog content have changed since it was computed.
+type resolutionCache struct {
+	mu sync.Mutex
+	// generation is incremented whenever catalog content may have changed.
+	generation uint64
+	key        string
+	solution   *solver.Solution
+}
+
+// get returns the cached solution for key, if there is one, and the current
+// generation, which must be passed to set along with the solution for key.
+func (c *resolutionCache) get(key string) (*solver.Solution, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.solution != nil && c.key == key {
+		return c.solution, c.generation
+	}
+	return nil, c.generation
+}
+
+// set caches solution for key, unless the cache was invalidated after
+// generation was returned by get.
+func (c *resolutionCache) set(key string, generation uint64, solution *solver.Solution) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	c.key = key
+	c.solution = solution
+}
+
+// invalidate drops the cached solution.
+func (c *resolutionCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.key = ""
+	c.solution = nil
+}
+
+// catalogContentVersions returns the resource versions of every BundleMetadata
+// and Package by name. Catalog events can be seen before the catalog content
+// they announce, so the content itself is part of the resolution cache key.
+func catalogContentVersions(ctx context.Context, c client.Reader) (map[string]string, error) {
+	versions := map[string]string{}
+	for _, kind := range []string{"BundleMetadataList", "PackageList"} {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(catalogd.GroupVersion.WithKind(kind))
+		if err := c.List(ctx, list); err != nil {
+			return nil, err
+		}
+		for _, item := range list.Items {
+			versions[kind+"/"+item.GetName()] = item.GetResourceVersion()
+		}
+	}
+	return versions, nil
+}
+
+// installedBundleKeys returns the controller and image of every BundleDeployment
+// by name. The bundle an Operator has installed constrains upgrades, e.g. with
+// the ZStream upgrade policy, so it is part of the resolution cache key.
+func installedBundleKeys(ctx context.Context, c client.Reader) (map[string]string, error) {
+	bundleDeployments := &rukpakv1alpha1.BundleDeploymentList{}
+	if err := c.List(ctx, bundleDeployments); err != nil {
+		return nil, err
+	}
+	installed := map[string]string{}
+	for i := range bundleDeployments.Items {
+		bd := &bundleDeployments.Items[i]
+		var controller, image string
+		if owner := metav1.GetControllerOf(bd); owner != nil {
+			controller = string(owner.UID)
+		}
+		if bd.Spec.Template != nil {
+			image = bundleSourceResource(bd.Spec.Template.Spec.Source)
+		}
+		installed[bd.GetName()] = controller + "/" + image
+	}
+	return installed, nil
+}
+
+// resolutionInputsKey returns a key that changes whenever the spec fields of
+// operators that are used for resolution, their installed bundles, the catalog
+// content or the cluster's Kubernetes version change.
+func resolutionInputsKey(operators []operatorsv1alpha1.Operator, installed, catalogContent map[string]string, kubeVersion string) (string, error) {
+	type inputs struct {
+		Name              string                              `json:"name"`
+		PackageName       string                              `json:"packageName"`
+		Version           string                              `json:"version,omitempty"`
+		VersionPreference []string                            `json:"versionPreference,omitempty"`
+		VersionExcludes   []string                            `json:"versionExcludes,omitempty"`
+		Channel           string                              `json:"channel,omitempty"`
+		Channels          []operatorsv1alpha1.ChannelName     `json:"channels,omitempty"`
+		Catalog           *operatorsv1alpha1.CatalogReference `json:"catalog,omitempty"`
+		UpgradePolicy     operatorsv1alpha1.UpgradePolicy     `json:"upgradePolicy,omitempty"`
+		AdoptionPolicy    operatorsv1alpha1.AdoptionPolicy    `json:"adoptionPolicy,omitempty"`
+		InstalledBundle   string                              `json:"installedBundle,omitempty"`
+	}
+	all := make([]inputs, 0, len(operators))
+	for _, op := range operators {
+		all = append(all, inputs{
+			Name:              op.GetName(),
+			PackageName:       op.Spec.PackageName,
+			Version:           op.Spec.Version,
+			VersionPreference: op.Spec.VersionPreference,
+			VersionExcludes:   op.Spec.VersionExcludes,
+			Channel:           op.Spec.Channel,
+			Channels:          op.Spec.Channels,
+			Catalog:           op.Spec.Catalog,
+			UpgradePolicy:     op.Spec.UpgradePolicy,
+			AdoptionPolicy:    op.Spec.AdoptionPolicy,
+			InstalledBundle:   installed[op.GetName()],
+		})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	// Maps are marshaled with sorted keys.
+	data, err := json.Marshal(struct {
+		Operators      []inputs          `json:"operators"`
+		CatalogContent map[string]string `json:"catalogContent"`
+		KubeVersion    string            `json:"kubeVersion,omitempty"`
+	}{all, catalogContent, kubeVersion})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// startupJitter spreads the first reconcile of each Operator after the
+// controller starts over a window. Each Operator gets a fixed offset into the
+// window derived from its name, so the spread doesn't depend on the order the
+// Operators are listed in.
+type startupJitter struct {
+	window time.Duration
+	start  time.Time
+
+	mu      sync.Mutex
+	delayed map[string]bool
+}
+
+func newStartupJitter(window time.Duration, start time.Time) *startupJitter {
+	return &startupJitter{window: window, start: start, delayed: map[string]bool{}}
+}
+
+// delay returns how long the reconcile of the named Operator should be
+// delayed. Only the first reconcile within the window is delayed.
+func (j *startupJitter) delay(name string, now time.Time) time.Duration {
+	if j == nil || j.window <= 0 || now.Sub(j.start) >= j.window {
+		return 0
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.delayed[name] {
+		return 0
+	}
+	j.delayed[name] = true
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	offset := time.Duration(h.Sum64() % uint64(j.window))
+	return j.start.Add(offset).Sub(now)
+}
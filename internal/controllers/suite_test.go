@@ -23,7 +23,9 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
 	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -33,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/features"
 )
 
 // These tests use Ginkgo (BDD-style Go testing framework). Refer to
@@ -72,10 +75,24 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	err = rukpakv1alpha1.AddToScheme(sch)
 	Expect(err).NotTo(HaveOccurred())
+	err = catalogd.AddToScheme(sch)
+	Expect(err).NotTo(HaveOccurred())
+	err = batchv1.AddToScheme(sch)
+	Expect(err).NotTo(HaveOccurred())
 
 	cl, err = client.New(cfg, client.Options{Scheme: sch})
 	Expect(err).NotTo(HaveOccurred())
 	Expect(cl).NotTo(BeNil())
+
+	// The controllers are tested with every experimental feature enabled.
+	Expect(features.OperatorControllerFeatureGate.SetFromMap(map[string]bool{
+		string(features.SequentialUpgradeStrategy): true,
+		string(features.UpgradeConstraintPolicy):   true,
+		string(features.BundleOverride):            true,
+		string(features.LifecycleHooks):            true,
+		string(features.OperatorSets):              true,
+		string(features.ProvidedAPIIndex):          true,
+	})).To(Succeed())
 })
 
 var _ = AfterSuite(func() {
@@ -4,8 +4,10 @@ import (
 	"fmt"
 
 	"github.com/blang/semver/v4"
+	"k8s.io/component-base/featuregate"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/features"
 )
 
 type operatorCRValidatorFunc func(operator *operatorsv1alpha1.Operator) error
@@ -24,14 +26,98 @@ func validateSemver(operator *operatorsv1alpha1.Operator) error {
 	return nil
 }
 
+// validateVersionPreference validates that each of the operator's preferred
+// versions is a valid semver range.
+func validateVersionPreference(operator *operatorsv1alpha1.Operator) error {
+	if len(operator.Spec.VersionPreference) == 0 {
+		return nil
+	}
+	if operator.Spec.Version != "" {
+		return fmt.Errorf("invalid .spec.versionPreference: cannot be combined with .spec.version")
+	}
+	for _, versionRange := range operator.Spec.VersionPreference {
+		if _, err := semver.ParseRange(versionRange); err != nil {
+			return fmt.Errorf("invalid .spec.versionPreference: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateVersionExcludes validates that each of the operator's excluded
+// versions is a valid semver range.
+func validateVersionExcludes(operator *operatorsv1alpha1.Operator) error {
+	for _, versionRange := range operator.Spec.VersionExcludes {
+		if _, err := semver.ParseRange(versionRange); err != nil {
+			return fmt.Errorf("invalid .spec.versionExcludes: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateChannels validates that the operator's channels are not empty and
+// not combined with .spec.channel.
+func validateChannels(operator *operatorsv1alpha1.Operator) error {
+	if len(operator.Spec.Channels) == 0 {
+		return nil
+	}
+	if operator.Spec.Channel != "" {
+		return fmt.Errorf("invalid .spec.channels: cannot be combined with .spec.channel")
+	}
+	seen := map[operatorsv1alpha1.ChannelName]bool{}
+	for _, channel := range operator.Spec.Channels {
+		if channel == "" {
+			return fmt.Errorf("invalid .spec.channels: channel names must not be empty")
+		}
+		if seen[channel] {
+			return fmt.Errorf("invalid .spec.channels: channel %q is listed more than once", channel)
+		}
+		seen[channel] = true
+	}
+	return nil
+}
+
+func validateDependsOn(operator *operatorsv1alpha1.Operator) error {
+	for _, name := range operator.Spec.DependsOn {
+		if name == operator.GetName() {
+			return fmt.Errorf("invalid .spec.dependsOn: operator %q cannot depend on itself", name)
+		}
+	}
+	return nil
+}
+
+// validateFeatureGates validates that the operator only uses fields whose
+// feature gates are enabled.
+func validateFeatureGates(operator *operatorsv1alpha1.Operator) error {
+	gated := []struct {
+		field   string
+		set     bool
+		feature featuregate.Feature
+	}{
+		{".spec.upgradeStrategy", operator.Spec.UpgradeStrategy == operatorsv1alpha1.UpgradeStrategySequential, features.SequentialUpgradeStrategy},
+		{".spec.upgradeConstraintPolicy", operator.Spec.UpgradeConstraintPolicy != "", features.UpgradeConstraintPolicy},
+		{".spec.bundleOverride", operator.Spec.BundleOverride != nil, features.BundleOverride},
+		{".spec.lifecycleHooks", len(operator.Spec.LifecycleHooks) > 0, features.LifecycleHooks},
+	}
+	for _, g := range gated {
+		if g.set && !features.OperatorControllerFeatureGate.Enabled(g.feature) {
+			return fmt.Errorf("invalid %s: requires the %s feature gate", g.field, g.feature)
+		}
+	}
+	return nil
+}
+
 // ValidateOperatorSpec validates the operator spec, e.g. ensuring that .spec.version, if provided, is a valid SemVer
 func ValidateOperatorSpec(operator *operatorsv1alpha1.Operator) error {
 	validators := []operatorCRValidatorFunc{
 		validateSemver,
+		validateVersionPreference,
+		validateVersionExcludes,
+		validateChannels,
+		validateDependsOn,
+		validateFeatureGates,
 	}
 
-	// TODO: currently we only have a single validator, but more will likely be added in the future
-	//  we need to make a decision on whether we want to run all validators or stop at the first error. If the the former,
+	// TODO: we need to make a decision on whether we want to run all validators or stop at the first error. If the the former,
 	//  we should consider how to present this to the user in a way that is easy to understand and fix.
 	//  this issue is tracked here: https://github.com/operator-framework/operator-controller/issues/167
 	for _, validator := range validators {
@@ -3,13 +3,33 @@ package validators_test
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/operator-framework/operator-controller/api/v1alpha1"
 	"github.com/operator-framework/operator-controller/internal/controllers/validators"
+	"github.com/operator-framework/operator-controller/internal/features"
 )
 
 var _ = Describe("Validators", func() {
 	Describe("ValidateOperatorSpec", func() {
+		It("should return an error for fields whose feature gate is disabled", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					LifecycleHooks: []v1alpha1.LifecycleHook{{Name: "migrate"}},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError("invalid .spec.lifecycleHooks: requires the LifecycleHooks feature gate"))
+
+			By("enabling the feature gate")
+			Expect(features.OperatorControllerFeatureGate.SetFromMap(map[string]bool{string(features.LifecycleHooks): true})).To(Succeed())
+			DeferCleanup(func() {
+				Expect(features.OperatorControllerFeatureGate.SetFromMap(map[string]bool{string(features.LifecycleHooks): false})).To(Succeed())
+			})
+			err = validators.ValidateOperatorSpec(operator)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("should not return an error for valid SemVer", func() {
 			operator := &v1alpha1.Operator{
 				Spec: v1alpha1.OperatorSpec{
@@ -59,5 +79,83 @@ var _ = Describe("Validators", func() {
 			err := validators.ValidateOperatorSpec(operator)
 			Expect(err).NotTo(HaveOccurred())
 		})
+
+		It("should not return an error for valid version preferences", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					VersionPreference: []string{"2.x", ">=1.9.0 <1.10.0"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(Succeed())
+		})
+
+		It("should return an error for an invalid version preference", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					VersionPreference: []string{"2.x", "latest"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(MatchError(ContainSubstring("invalid .spec.versionPreference")))
+		})
+
+		It("should return an error for an invalid version exclude", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					VersionExcludes: []string{"1.2.3", "bad"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(MatchError(ContainSubstring("invalid .spec.versionExcludes")))
+		})
+
+		It("should return an error for version preferences combined with a version", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Version:           "1.2.3",
+					VersionPreference: []string{"2.x"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(MatchError("invalid .spec.versionPreference: cannot be combined with .spec.version"))
+		})
+
+		It("should return an error for channels combined with a channel", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Channel:  "stable",
+					Channels: []v1alpha1.ChannelName{"stable", "fast"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(MatchError("invalid .spec.channels: cannot be combined with .spec.channel"))
+		})
+
+		It("should return an error for duplicate channels", func() {
+			operator := &v1alpha1.Operator{
+				Spec: v1alpha1.OperatorSpec{
+					Channels: []v1alpha1.ChannelName{"stable", "stable"},
+				},
+			}
+			Expect(validators.ValidateOperatorSpec(operator)).To(MatchError(`invalid .spec.channels: channel "stable" is listed more than once`))
+		})
+
+		It("should return an error when the operator depends on itself", func() {
+			operator := &v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: v1alpha1.OperatorSpec{
+					DependsOn: []string{"bar", "foo"},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).To(MatchError(`invalid .spec.dependsOn: operator "foo" cannot depend on itself`))
+		})
+
+		It("should not return an error when the operator depends on other operators", func() {
+			operator := &v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+				Spec: v1alpha1.OperatorSpec{
+					DependsOn: []string{"bar"},
+				},
+			}
+			err := validators.ValidateOperatorSpec(operator)
+			Expect(err).NotTo(HaveOccurred())
+		})
 	})
 })
@@ -0,0 +1,145 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package defaults applies OperatorDefaults to Operators.
+package defaults
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// List returns every OperatorDefaults, ordered by name.
+func List(ctx context.Context, c client.Reader) ([]operatorsv1alpha1.OperatorDefaults, error) {
+	list := &operatorsv1alpha1.OperatorDefaultsList{}
+	if err := c.List(ctx, list); err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+	return list.Items, nil
+}
+
+// Apply sets the spec fields op leaves unset from the defaults that select
+// it. defaults are expected in name order, as returned by List; the first
+// one that sets a field wins.
+func Apply(op *operatorsv1alpha1.Operator, defaults []operatorsv1alpha1.OperatorDefaults) error {
+	selected, err := selecting(op, defaults)
+	if err != nil {
+		return err
+	}
+	for _, d := range selected {
+		if op.Spec.UpgradeStrategy == "" {
+			op.Spec.UpgradeStrategy = d.Spec.UpgradeStrategy
+		}
+		if op.Spec.UpgradePolicy == "" {
+			op.Spec.UpgradePolicy = d.Spec.UpgradePolicy
+		}
+		if op.Spec.DeletionPolicy == "" {
+			op.Spec.DeletionPolicy = d.Spec.DeletionPolicy
+		}
+		if op.Spec.ProgressDeadlineSeconds == nil && d.Spec.ProgressDeadlineSeconds != nil {
+			seconds := *d.Spec.ProgressDeadlineSeconds
+			op.Spec.ProgressDeadlineSeconds = &seconds
+		}
+	}
+	return nil
+}
+
+// Conflicts describes each field op leaves unset that the defaults selecting
+// it set to different values, and which value Apply uses. defaults are
+// expected in name order, as returned by List.
+func Conflicts(op *operatorsv1alpha1.Operator, defaults []operatorsv1alpha1.OperatorDefaults) ([]string, error) {
+	selected, err := selecting(op, defaults)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	conflict := func(field string, unset bool, value func(operatorsv1alpha1.OperatorDefaultsSpec) string) {
+		if !unset {
+			return
+		}
+		var used, usedBy string
+		var others []string
+		for _, d := range selected {
+			v := value(d.Spec)
+			switch {
+			case v == "":
+			case usedBy == "":
+				used, usedBy = v, d.GetName()
+			case v != used:
+				others = append(others, fmt.Sprintf("%s from %q", v, d.GetName()))
+			}
+		}
+		if len(others) > 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%s: using %s from %q over %s", field, used, usedBy, strings.Join(others, ", ")))
+		}
+	}
+	conflict("upgradeStrategy", op.Spec.UpgradeStrategy == "", func(s operatorsv1alpha1.OperatorDefaultsSpec) string {
+		return string(s.UpgradeStrategy)
+	})
+	conflict("upgradePolicy", op.Spec.UpgradePolicy == "", func(s operatorsv1alpha1.OperatorDefaultsSpec) string {
+		return string(s.UpgradePolicy)
+	})
+	conflict("deletionPolicy", op.Spec.DeletionPolicy == "", func(s operatorsv1alpha1.OperatorDefaultsSpec) string {
+		return string(s.DeletionPolicy)
+	})
+	conflict("progressDeadlineSeconds", op.Spec.ProgressDeadlineSeconds == nil, func(s operatorsv1alpha1.OperatorDefaultsSpec) string {
+		if s.ProgressDeadlineSeconds == nil {
+			return ""
+		}
+		return strconv.Itoa(int(*s.ProgressDeadlineSeconds))
+	})
+	return conflicts, nil
+}
+
+// selecting returns the defaults whose operator selector matches op.
+func selecting(op *operatorsv1alpha1.Operator, defaults []operatorsv1alpha1.OperatorDefaults) ([]operatorsv1alpha1.OperatorDefaults, error) {
+	var selected []operatorsv1alpha1.OperatorDefaults
+	for _, d := range defaults {
+		selector, err := metav1.LabelSelectorAsSelector(&d.Spec.OperatorSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid operator selector of operator defaults %q: %w", d.GetName(), err)
+		}
+		if selector.Matches(labels.Set(op.GetLabels())) {
+			selected = append(selected, d)
+		}
+	}
+	return selected, nil
+}
+
+// ApplyAll applies the OperatorDefaults in the cluster to every Operator in
+// operators.
+func ApplyAll(ctx context.Context, c client.Reader, operators []operatorsv1alpha1.Operator) error {
+	defaults, err := List(ctx, c)
+	if err != nil {
+		return err
+	}
+	for i := range operators {
+		if err := Apply(&operators[i], defaults); err != nil {
+			return err
+		}
+	}
+	return nil
+}
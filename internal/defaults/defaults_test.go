@@ -0,0 +1,103 @@
+package defaults_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/defaults"
+)
+
+var _ = Describe("Defaults", func() {
+	operatorDefaults := func(name string, selector map[string]string, spec operatorsv1alpha1.OperatorDefaultsSpec) operatorsv1alpha1.OperatorDefaults {
+		spec.OperatorSelector = metav1.LabelSelector{MatchLabels: selector}
+		return operatorsv1alpha1.OperatorDefaults{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: spec}
+	}
+
+	Describe("Apply", func() {
+		It("sets unset fields from the defaults that select the operator", func() {
+			op := &operatorsv1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}},
+				Spec: operatorsv1alpha1.OperatorSpec{
+					PackageName:    "prometheus",
+					DeletionPolicy: operatorsv1alpha1.DeletionPolicyDelete,
+				},
+			}
+			Expect(defaults.Apply(op, []operatorsv1alpha1.OperatorDefaults{
+				operatorDefaults("a", map[string]string{"team": "b"}, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy: operatorsv1alpha1.UpgradeStrategyDirect,
+				}),
+				operatorDefaults("b", map[string]string{"team": "a"}, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy:         operatorsv1alpha1.UpgradeStrategySequential,
+					DeletionPolicy:          operatorsv1alpha1.DeletionPolicyBlock,
+					ProgressDeadlineSeconds: pointer.Int32(60),
+				}),
+				operatorDefaults("c", nil, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy: operatorsv1alpha1.UpgradeStrategyDirect,
+					UpgradePolicy:   operatorsv1alpha1.UpgradePolicyZStream,
+				}),
+			})).To(Succeed())
+			Expect(op.Spec.UpgradeStrategy).To(Equal(operatorsv1alpha1.UpgradeStrategySequential))
+			Expect(op.Spec.UpgradePolicy).To(Equal(operatorsv1alpha1.UpgradePolicyZStream))
+			Expect(op.Spec.DeletionPolicy).To(Equal(operatorsv1alpha1.DeletionPolicyDelete))
+			Expect(op.Spec.ProgressDeadlineSeconds).To(Equal(pointer.Int32(60)))
+		})
+
+		It("returns an error for an invalid selector", func() {
+			d := operatorDefaults("a", nil, operatorsv1alpha1.OperatorDefaultsSpec{})
+			d.Spec.OperatorSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Bogus"}}
+			err := defaults.Apply(&operatorsv1alpha1.Operator{}, []operatorsv1alpha1.OperatorDefaults{d})
+			Expect(err).To(MatchError(ContainSubstring(`invalid operator selector of operator defaults "a"`)))
+		})
+	})
+
+	Describe("Conflicts", func() {
+		It("reports fields the selecting defaults set to different values", func() {
+			op := &operatorsv1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "a"}},
+				Spec:       operatorsv1alpha1.OperatorSpec{DeletionPolicy: operatorsv1alpha1.DeletionPolicyDelete},
+			}
+			conflicts, err := defaults.Conflicts(op, []operatorsv1alpha1.OperatorDefaults{
+				operatorDefaults("a", map[string]string{"team": "a"}, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy:         operatorsv1alpha1.UpgradeStrategySequential,
+					DeletionPolicy:          operatorsv1alpha1.DeletionPolicyBlock,
+					ProgressDeadlineSeconds: pointer.Int32(60),
+				}),
+				operatorDefaults("b", map[string]string{"team": "b"}, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy: operatorsv1alpha1.UpgradeStrategyDirect,
+				}),
+				operatorDefaults("c", nil, operatorsv1alpha1.OperatorDefaultsSpec{
+					UpgradeStrategy:         operatorsv1alpha1.UpgradeStrategyDirect,
+					UpgradePolicy:           operatorsv1alpha1.UpgradePolicyZStream,
+					DeletionPolicy:          operatorsv1alpha1.DeletionPolicyDelete,
+					ProgressDeadlineSeconds: pointer.Int32(60),
+				}),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conflicts).To(Equal([]string{`upgradeStrategy: using Sequential from "a" over Direct from "c"`}))
+		})
+	})
+
+	Describe("ApplyAll", func() {
+		It("applies the defaults in name order", func() {
+			scheme := runtime.NewScheme()
+			utilruntime.Must(operatorsv1alpha1.AddToScheme(scheme))
+			z := operatorDefaults("z", nil, operatorsv1alpha1.OperatorDefaultsSpec{UpgradePolicy: operatorsv1alpha1.UpgradePolicyAutomatic})
+			a := operatorDefaults("a", nil, operatorsv1alpha1.OperatorDefaultsSpec{UpgradePolicy: operatorsv1alpha1.UpgradePolicyZStream})
+			cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&z, &a).Build()
+
+			operators := []operatorsv1alpha1.Operator{{}, {}}
+			Expect(defaults.ApplyAll(context.Background(), cl, operators)).To(Succeed())
+			for _, op := range operators {
+				Expect(op.Spec.UpgradePolicy).To(Equal(operatorsv1alpha1.UpgradePolicyZStream))
+			}
+		})
+	})
+})
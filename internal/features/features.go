@@ -0,0 +1,87 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package features
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// SequentialUpgradeStrategy allows spec.upgradeStrategy: Sequential.
+	SequentialUpgradeStrategy featuregate.Feature = "SequentialUpgradeStrategy"
+	// UpgradeConstraintPolicy allows spec.upgradeConstraintPolicy.
+	UpgradeConstraintPolicy featuregate.Feature = "UpgradeConstraintPolicy"
+	// BundleOverride allows spec.bundleOverride.
+	BundleOverride featuregate.Feature = "BundleOverride"
+	// LifecycleHooks allows spec.lifecycleHooks.
+	LifecycleHooks featuregate.Feature = "LifecycleHooks"
+	// OperatorSets runs the OperatorSet controller.
+	OperatorSets featuregate.Feature = "OperatorSets"
+	// ProvidedAPIIndex runs the ProvidedAPI controller, and uses the index it
+	// maintains to detect API conflicts and instances that block deletion.
+	ProvidedAPIIndex featuregate.Feature = "ProvidedAPIIndex"
+)
+
+// operatorControllerFeatureGates holds the known feature gates. New
+// experimental behaviors should declare a featuregate.Feature constant,
+// register it here as Alpha and disabled by default, and be guarded with
+// OperatorControllerFeatureGate.Enabled.
+var operatorControllerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	SequentialUpgradeStrategy: {Default: false, PreRelease: featuregate.Alpha},
+	UpgradeConstraintPolicy:   {Default: false, PreRelease: featuregate.Alpha},
+	BundleOverride:            {Default: false, PreRelease: featuregate.Alpha},
+	LifecycleHooks:            {Default: false, PreRelease: featuregate.Alpha},
+	OperatorSets:              {Default: false, PreRelease: featuregate.Alpha},
+	ProvidedAPIIndex:          {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// OperatorControllerFeatureGate is the feature gate for operator-controller.
+// It is set from the --feature-gates flag and the featureGates of the config
+// file.
+var OperatorControllerFeatureGate featuregate.MutableFeatureGate = featuregate.NewFeatureGate()
+
+var featureEnabled = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "operator_controller_feature_enabled",
+		Help: "Whether an operator-controller feature gate is enabled (1) or disabled (0).",
+	},
+	[]string{"name", "stage"},
+)
+
+func init() {
+	utilruntime.Must(OperatorControllerFeatureGate.Add(operatorControllerFeatureGates))
+	metrics.Registry.MustRegister(featureEnabled)
+}
+
+// RecordMetrics publishes the state of every known feature gate. It should be
+// called once the feature gates have been set from flags and the config file.
+func RecordMetrics() {
+	recordMetrics(OperatorControllerFeatureGate, operatorControllerFeatureGates)
+}
+
+func recordMetrics(gate featuregate.FeatureGate, known map[featuregate.Feature]featuregate.FeatureSpec) {
+	for feature, spec := range known {
+		value := 0.0
+		if gate.Enabled(feature) {
+			value = 1.0
+		}
+		featureEnabled.WithLabelValues(string(feature), string(spec.PreRelease)).Set(value)
+	}
+}
@@ -0,0 +1,32 @@
+package features
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/component-base/featuregate"
+)
+
+func TestFeatures(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Features Suite")
+}
+
+var _ = Describe("Feature gate metrics", func() {
+	It("reports the enabled state of every known feature gate", func() {
+		known := map[featuregate.Feature]featuregate.FeatureSpec{
+			"AlphaFeature": {Default: false, PreRelease: featuregate.Alpha},
+			"BetaFeature":  {Default: true, PreRelease: featuregate.Beta},
+		}
+		gate := featuregate.NewFeatureGate()
+		Expect(gate.Add(known)).To(Succeed())
+		Expect(gate.Set("AlphaFeature=true,BetaFeature=false")).To(Succeed())
+
+		recordMetrics(gate, known)
+
+		Expect(testutil.ToFloat64(featureEnabled.WithLabelValues("AlphaFeature", string(featuregate.Alpha)))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(featureEnabled.WithLabelValues("BetaFeature", string(featuregate.Beta)))).To(Equal(0.0))
+	})
+})
@@ -0,0 +1,86 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubeversion reads the cluster's Kubernetes version for resolution.
+package kubeversion
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"k8s.io/client-go/discovery"
+)
+
+// Source reads the cluster's Kubernetes version from the API server and caches
+// it for a TTL, so that resolving bundles against olm.minKubeVersion follows an
+// in-place control plane upgrade without a restart of operator-controller.
+type Source struct {
+	client discovery.ServerVersionInterface
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	version *semver.Version
+	readAt  time.Time
+}
+
+// NewSource returns a Source that reads the server version with client at most
+// once per ttl.
+func NewSource(client discovery.ServerVersionInterface, ttl time.Duration) *Source {
+	return &Source{client: client, ttl: ttl, now: time.Now}
+}
+
+// KubeVersion returns the cluster's Kubernetes version. If re-reading an expired
+// version fails, the last version read is returned until the next attempt.
+func (s *Source) KubeVersion(_ context.Context) (semver.Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.version != nil && s.now().Sub(s.readAt) < s.ttl {
+		return *s.version, nil
+	}
+	v, err := s.read()
+	s.readAt = s.now()
+	if err != nil {
+		if s.version != nil {
+			return *s.version, nil
+		}
+		return semver.Version{}, err
+	}
+	s.version = &v
+	return v, nil
+}
+
+func (s *Source) read() (semver.Version, error) {
+	info, err := s.client.ServerVersion()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	v, err := semver.ParseTolerant(info.GitVersion)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("parsing server version %q: %w", info.GitVersion, err)
+	}
+	return Normalize(v), nil
+}
+
+// Normalize drops the pre-release and build parts of v, which distributions
+// use for their own versioning, e.g. v1.27.3-gke.100 or v1.27.3+k3s1. Compared
+// as is, they would make the cluster look older than the release it is based on.
+func Normalize(v semver.Version) semver.Version {
+	return semver.Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
@@ -0,0 +1,89 @@
+package kubeversion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blang/semver/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestKubeVersion(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "KubeVersion Suite")
+}
+
+type fakeServerVersion struct {
+	gitVersion string
+	err        error
+	calls      int
+}
+
+func (f *fakeServerVersion) ServerVersion() (*version.Info, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &version.Info{GitVersion: f.gitVersion}, nil
+}
+
+var _ = Describe("Source", func() {
+	var (
+		server *fakeServerVersion
+		source *Source
+		now    time.Time
+	)
+	BeforeEach(func() {
+		server = &fakeServerVersion{gitVersion: "v1.26.4-gke.100"}
+		source = NewSource(server, time.Minute)
+		now = time.Now()
+		source.now = func() time.Time { return now }
+	})
+	It("returns the normalized server version", func() {
+		v, err := source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(semver.MustParse("1.26.4")))
+	})
+	It("reads the server version again once the TTL expires", func() {
+		_, err := source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		By("upgrading the control plane")
+		server.gitVersion = "v1.27.1"
+		v, err := source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(semver.MustParse("1.26.4")))
+		Expect(server.calls).To(Equal(1))
+
+		By("letting the TTL expire")
+		now = now.Add(time.Minute)
+		v, err = source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(semver.MustParse("1.27.1")))
+		Expect(server.calls).To(Equal(2))
+	})
+	It("keeps the last version if reading it again fails", func() {
+		_, err := source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+
+		server.err = errors.New("connection refused")
+		now = now.Add(time.Minute)
+		v, err := source.KubeVersion(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v).To(Equal(semver.MustParse("1.26.4")))
+	})
+	It("returns an error if the version was never read", func() {
+		server.err = errors.New("connection refused")
+		_, err := source.KubeVersion(context.Background())
+		Expect(err).To(MatchError("connection refused"))
+	})
+	It("returns an error for a version that is not semver", func() {
+		server.gitVersion = "latest"
+		_, err := source.KubeVersion(context.Background())
+		Expect(err).To(MatchError(ContainSubstring(`parsing server version "latest"`)))
+	})
+})
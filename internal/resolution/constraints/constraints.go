@@ -0,0 +1,176 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package constraints evaluates olm.constraint bundle properties that carry a
+// CEL rule against the properties of bundles that are already installed.
+package constraints
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/interpreter"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+)
+
+// PropertyConstraint is the type of bundle properties that declare a constraint.
+const PropertyConstraint = "olm.constraint"
+
+// Constraint is the value of an olm.constraint property. Only constraints with
+// a cel rule are supported; other constraint types are ignored.
+type Constraint struct {
+	FailureMessage string         `json:"failureMessage,omitempty"`
+	Cel            *CelConstraint `json:"cel,omitempty"`
+}
+
+// CelConstraint is a CEL rule evaluated against a bundle's properties, which
+// are available to the rule as `properties`, a list of {type, value} maps.
+// The semver_compare(a, b) function compares two semver strings and returns
+// -1, 0 or 1. Evaluating the rule against one bundle may not exceed CostLimit.
+type CelConstraint struct {
+	Rule string `json:"rule"`
+}
+
+const (
+	// CostLimit is the maximum cost of evaluating a rule against the properties
+	// of one bundle. Rules come from catalog content, so a rule that exceeds it
+	// is rejected rather than allowed to stall reconciles.
+	CostLimit = 1000000
+
+	// interruptCheckFrequency is how many comprehension iterations are
+	// evaluated between checks for a cancelled context.
+	interruptCheckFrequency = 100
+
+	// maxCachedPrograms bounds the number of compiled rules that are kept.
+	maxCachedPrograms = 512
+)
+
+// Evaluator compiles and evaluates CEL constraint rules. Compiled rules are
+// cached, so an Evaluator should be reused. It is safe for concurrent use.
+type Evaluator struct {
+	env *cel.Env
+
+	mu       sync.Mutex
+	programs map[string]cel.Program
+}
+
+// NewEvaluator returns an Evaluator.
+func NewEvaluator() (*Evaluator, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("properties", cel.ListType(cel.MapType(cel.StringType, cel.DynType))),
+		cel.Function("semver_compare",
+			cel.Overload("semver_compare_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+				cel.BinaryBinding(semverCompare))),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Evaluator{env: env, programs: map[string]cel.Program{}}, nil
+}
+
+// Satisfied returns true if rule holds for the properties of at least one of
+// the given bundles. An error is returned if the rule is not a valid boolean
+// expression, if evaluating it exceeds CostLimit or if ctx is done.
+func (e *Evaluator) Satisfied(ctx context.Context, rule string, bundles [][]catalogd.Property) (bool, error) {
+	prg, err := e.program(rule)
+	if err != nil {
+		return false, err
+	}
+	for _, props := range bundles {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		activation, err := propertiesActivation(props)
+		if err != nil {
+			return false, err
+		}
+		out, _, err := prg.ContextEval(ctx, activation)
+		var cancelled interpreter.EvalCancelledError
+		if errors.As(err, &cancelled) {
+			if cancelled.Cause == interpreter.CostLimitExceeded {
+				return false, fmt.Errorf("rule %q exceeds the cost limit of %d", rule, CostLimit)
+			}
+			return false, ctx.Err()
+		}
+		// Rules that fail to evaluate against a bundle, e.g. because they
+		// expect a property value of a different shape, don't match it.
+		if err != nil {
+			continue
+		}
+		if ok, isBool := out.Value().(bool); isBool && ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// program returns the compiled program for rule, compiling it on first use.
+func (e *Evaluator) program(rule string) (cel.Program, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if prg, ok := e.programs[rule]; ok {
+		return prg, nil
+	}
+
+	ast, iss := e.env.Compile(rule)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", rule, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("invalid rule %q: must evaluate to a bool, not %s", rule, ast.OutputType())
+	}
+	prg, err := e.env.Program(ast, cel.CostLimit(CostLimit), cel.InterruptCheckFrequency(interruptCheckFrequency))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", rule, err)
+	}
+	if len(e.programs) >= maxCachedPrograms {
+		e.programs = map[string]cel.Program{}
+	}
+	e.programs[rule] = prg
+	return prg, nil
+}
+
+func propertiesActivation(props []catalogd.Property) (map[string]interface{}, error) {
+	values := make([]interface{}, 0, len(props))
+	for _, p := range props {
+		var value interface{}
+		if err := json.Unmarshal(p.Value, &value); err != nil {
+			return nil, fmt.Errorf("property %q could not be parsed: %w", p.Type, err)
+		}
+		values = append(values, map[string]interface{}{"type": p.Type, "value": value})
+	}
+	return map[string]interface{}{"properties": values}, nil
+}
+
+func semverCompare(lhs, rhs ref.Val) ref.Val {
+	a, err := semver.Parse(string(lhs.(types.String)))
+	if err != nil {
+		return types.NewErr("semver_compare: %v", err)
+	}
+	b, err := semver.Parse(string(rhs.(types.String)))
+	if err != nil {
+		return types.NewErr("semver_compare: %v", err)
+	}
+	return types.Int(a.Compare(b))
+}
@@ -0,0 +1,96 @@
+package constraints_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/constraints"
+)
+
+func TestConstraints(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Constraints Suite")
+}
+
+func packageProperty(name, version string) catalogd.Property {
+	return catalogd.Property{
+		Type:  "olm.package",
+		Value: json.RawMessage(`{"packageName":"` + name + `","version":"` + version + `"}`),
+	}
+}
+
+var _ = Describe("Evaluator", func() {
+	var (
+		evaluator *constraints.Evaluator
+		installed [][]catalogd.Property
+	)
+	BeforeEach(func() {
+		var err error
+		evaluator, err = constraints.NewEvaluator()
+		Expect(err).NotTo(HaveOccurred())
+		installed = [][]catalogd.Property{
+			{packageProperty("cert-manager", "1.11.0")},
+			{
+				packageProperty("prometheus", "0.47.0"),
+				{Type: "olm.gvk", Value: json.RawMessage(`{"group":"monitoring.coreos.com","kind":"Prometheus","version":"v1"}`)},
+			},
+		}
+	})
+	It("is satisfied when any installed bundle matches the rule", func() {
+		ok, err := evaluator.Satisfied(context.Background(), `properties.exists(p, p.type == "olm.gvk" && p.value.kind == "Prometheus")`, installed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+	It("is not satisfied when no installed bundle matches the rule", func() {
+		ok, err := evaluator.Satisfied(context.Background(), `properties.exists(p, p.type == "olm.package" && p.value.packageName == "etcd")`, installed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+	It("is not satisfied when nothing is installed", func() {
+		ok, err := evaluator.Satisfied(context.Background(), `true`, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+	It("compares versions with semver_compare", func() {
+		rule := `properties.exists(p, p.type == "olm.package" && p.value.packageName == "cert-manager" && semver_compare(p.value.version, "1.10.0") >= 0)`
+		ok, err := evaluator.Satisfied(context.Background(), rule, installed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+
+		rule = `properties.exists(p, p.type == "olm.package" && p.value.packageName == "cert-manager" && semver_compare(p.value.version, "1.12.0") >= 0)`
+		ok, err = evaluator.Satisfied(context.Background(), rule, installed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+	It("skips bundles the rule fails to evaluate against", func() {
+		ok, err := evaluator.Satisfied(context.Background(), `properties.exists(p, p.value.kind == "Prometheus")`, installed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+	})
+	It("rejects rules that do not compile", func() {
+		_, err := evaluator.Satisfied(context.Background(), `properties.exists(p,`, installed)
+		Expect(err).To(MatchError(ContainSubstring(`invalid rule "properties.exists(p,"`)))
+	})
+	It("rejects rules that do not evaluate to a bool", func() {
+		_, err := evaluator.Satisfied(context.Background(), `size(properties)`, installed)
+		Expect(err).To(MatchError(ContainSubstring("must evaluate to a bool")))
+	})
+	It("rejects rules that exceed the cost limit", func() {
+		rule := `[0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(a, [0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(b, [0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(c,
+			[0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(d, [0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(e, [0, 1, 2, 3, 4, 5, 6, 7, 8, 9].all(f, a + b + c + d + e + f >= 0))))))`
+		_, err := evaluator.Satisfied(context.Background(), rule, installed)
+		Expect(err).To(MatchError(ContainSubstring("exceeds the cost limit of 1000000")))
+	})
+	It("stops evaluating once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := evaluator.Satisfied(ctx, `properties.exists(p, p.type == "olm.package")`, installed)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+})
@@ -3,27 +3,69 @@ package entitysources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
 	"github.com/operator-framework/operator-registry/alpha/property"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
 )
 
+// propertyCSVMetadata is the property registry+v1 bundles carry their
+// ClusterServiceVersion's metadata in, including its minKubeVersion.
+const propertyCSVMetadata = "olm.csv.metadata"
+
+// ReasonInvalidBundleMetadata is the reason of the Warning events recorded on
+// a Catalog for each of its bundles that is skipped because it is malformed.
+const ReasonInvalidBundleMetadata = "InvalidBundleMetadata"
+
+var invalidBundles = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "operator_controller_catalog_invalid_bundles",
+		Help: "Number of bundles of a catalog that are skipped during resolution because their metadata is malformed.",
+	},
+	[]string{"catalog"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(invalidBundles)
+}
+
 // catalogdEntitySource is a source for(/collection of) deppy defined input.Entity, built from content
 // made accessible on-cluster by https://github.com/operator-framework/catalogd.
 // It is an implementation of deppy defined input.EntitySource
 type catalogdEntitySource struct {
-	client client.Client
+	client   client.Client
+	recorder record.EventRecorder
+
+	// reported holds the error last reported for each invalid bundle, so that
+	// a bundle is only logged and recorded again when its error changes.
+	mu       sync.Mutex
+	reported map[string]string
 }
 
 func NewCatalogdEntitySource(client client.Client) *catalogdEntitySource {
 
-	return &catalogdEntitySource{client: client}
+	return &catalogdEntitySource{client: client, reported: map[string]string{}}
+}
+
+// WithEventRecorder makes the entity source record a Warning event on a
+// Catalog for each of its bundles that is skipped because it is malformed.
+func (es *catalogdEntitySource) WithEventRecorder(recorder record.EventRecorder) *catalogdEntitySource {
+	es.recorder = recorder
+	return es
 }
 
 func (es *catalogdEntitySource) Get(ctx context.Context, id deppy.Identifier) (*input.Entity, error) {
@@ -32,7 +74,7 @@ func (es *catalogdEntitySource) Get(ctx context.Context, id deppy.Identifier) (*
 
 func (es *catalogdEntitySource) Filter(ctx context.Context, filter input.Predicate) (input.EntityList, error) {
 	resultSet := input.EntityList{}
-	entities, err := getEntities(ctx, es.client)
+	entities, err := es.getEntities(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +87,7 @@ func (es *catalogdEntitySource) Filter(ctx context.Context, filter input.Predica
 }
 
 func (es *catalogdEntitySource) GroupBy(ctx context.Context, fn input.GroupByFunction) (input.EntityListMap, error) {
-	entities, err := getEntities(ctx, es.client)
+	entities, err := es.getEntities(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +102,7 @@ func (es *catalogdEntitySource) GroupBy(ctx context.Context, fn input.GroupByFun
 }
 
 func (es *catalogdEntitySource) Iterate(ctx context.Context, fn input.IteratorFunction) error {
-	entities, err := getEntities(ctx, es.client)
+	entities, err := es.getEntities(ctx)
 	if err != nil {
 		return err
 	}
@@ -72,13 +114,24 @@ func (es *catalogdEntitySource) Iterate(ctx context.Context, fn input.IteratorFu
 	return nil
 }
 
-func getEntities(ctx context.Context, client client.Client) (input.EntityList, error) {
+// getEntities returns the entities of every valid bundle. Malformed bundles
+// are skipped, so that they only affect the packages they belong to, and are
+// reported against their catalog.
+func (es *catalogdEntitySource) getEntities(ctx context.Context) (input.EntityList, error) {
 	entities := input.EntityList{}
-	bundleMetadatas, packageMetdatas, err := fetchMetadata(ctx, client)
+	bundleMetadatas, packageMetdatas, catalogs, err := fetchMetadata(ctx, es.client)
 	if err != nil {
 		return nil, err
 	}
+	entries := channelEntriesByBundle(packageMetdatas)
+	var invalid []invalidBundle
 	for _, bundle := range bundleMetadatas.Items {
+		catalogScopedPkgName := fmt.Sprintf("%s-%s", bundle.Spec.Catalog.Name, bundle.Spec.Package)
+		bundleEntries := entries[catalogScopedPkgName][bundle.Name]
+		if err := validateBundle(bundle, len(bundleEntries) > 0); err != nil {
+			invalid = append(invalid, invalidBundle{bundle: bundle, err: err})
+			continue
+		}
 		props := map[string]string{}
 
 		// TODO: We should make sure all properties are forwarded
@@ -91,6 +144,20 @@ func getEntities(ctx context.Context, client client.Client) (input.EntityList, e
 				props[property.TypePackage] = string(prop.Value)
 			case entity.PropertyBundleMediaType:
 				props[entity.PropertyBundleMediaType] = string(prop.Value)
+			case propertyCSVMetadata:
+				var csvMetadata struct {
+					MinKubeVersion string `json:"minKubeVersion,omitempty"`
+				}
+				if err := json.Unmarshal(prop.Value, &csvMetadata); err != nil {
+					return nil, fmt.Errorf("parsing %s property of bundle %q: %w", propertyCSVMetadata, bundle.Name, err)
+				}
+				if csvMetadata.MinKubeVersion != "" {
+					minKubeVersionValue, err := json.Marshal(csvMetadata.MinKubeVersion)
+					if err != nil {
+						return nil, err
+					}
+					props[entity.PropertyBundleMinKubeVersion] = string(minKubeVersionValue)
+				}
 			}
 		}
 
@@ -99,38 +166,174 @@ func getEntities(ctx context.Context, client client.Client) (input.EntityList, e
 			return nil, err
 		}
 		props[entity.PropertyBundlePath] = string(imgValue)
-		catalogScopedPkgName := fmt.Sprintf("%s-%s", bundle.Spec.Catalog.Name, bundle.Spec.Package)
-		bundlePkg := packageMetdatas[catalogScopedPkgName]
-		for _, ch := range bundlePkg.Spec.Channels {
-			for _, b := range ch.Entries {
-				catalogScopedEntryName := fmt.Sprintf("%s-%s", bundle.Spec.Catalog.Name, b.Name)
-				if catalogScopedEntryName == bundle.Name {
-					channelValue, _ := json.Marshal(property.Channel{ChannelName: ch.Name, Priority: 0})
-					props[property.TypeChannel] = string(channelValue)
-					entity := input.Entity{
-						ID:         deppy.IdentifierFromString(fmt.Sprintf("%s%s%s", bundle.Name, bundle.Spec.Package, ch.Name)),
-						Properties: props,
-					}
-					entities = append(entities, entity)
-				}
+
+		catalogValue, err := json.Marshal(entity.Catalog{Name: bundle.Spec.Catalog.Name, Ref: catalogs[bundle.Spec.Catalog.Name]})
+		if err != nil {
+			return nil, err
+		}
+		props[entity.PropertyBundleCatalog] = string(catalogValue)
+		for _, e := range bundleEntries {
+			// Each entity gets its own copy of the properties, as the
+			// channel property differs between channels.
+			entityProps := make(map[string]string, len(props)+2)
+			for k, v := range props {
+				entityProps[k] = v
+			}
+			channelValue, err := json.Marshal(entity.ChannelProperties{
+				Channel:   property.Channel{ChannelName: e.channel, Priority: 0},
+				Replaces:  e.Replaces,
+				Skips:     e.Skips,
+				SkipRange: e.SkipRange,
+			})
+			if err != nil {
+				return nil, err
 			}
+			entityProps[property.TypeChannel] = string(channelValue)
+			nameValue, err := json.Marshal(e.Name)
+			if err != nil {
+				return nil, err
+			}
+			entityProps[entity.PropertyBundleName] = string(nameValue)
+			entities = append(entities, input.Entity{
+				ID:         deppy.IdentifierFromString(fmt.Sprintf("%s%s%s", bundle.Name, bundle.Spec.Package, e.channel)),
+				Properties: entityProps,
+			})
 		}
 	}
+	es.reportInvalidBundles(ctx, catalogs, invalid)
 	return entities, nil
 }
 
-func fetchMetadata(ctx context.Context, client client.Client) (catalogd.BundleMetadataList, map[string]catalogd.Package, error) {
+// invalidBundle is a bundle that is skipped because it is malformed.
+type invalidBundle struct {
+	bundle catalogd.BundleMetadata
+	err    error
+}
+
+// validateBundle returns an error describing why bundle can't be resolved,
+// or nil if it can. inChannel is whether any channel of its package has an
+// entry for it.
+func validateBundle(bundle catalogd.BundleMetadata, inChannel bool) error {
+	var errs []error
+	foundPackage := false
+	for _, prop := range bundle.Spec.Properties {
+		switch prop.Type {
+		case property.TypePackage:
+			foundPackage = true
+			pkg := property.Package{}
+			if err := json.Unmarshal(prop.Value, &pkg); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s property: %w", property.TypePackage, err))
+				continue
+			}
+			if pkg.PackageName != bundle.Spec.Package {
+				errs = append(errs, fmt.Errorf("%s property names package %q instead of %q", property.TypePackage, pkg.PackageName, bundle.Spec.Package))
+			}
+			if _, err := semver.Parse(pkg.Version); err != nil {
+				errs = append(errs, fmt.Errorf("invalid version %q in %s property: %w", pkg.Version, property.TypePackage, err))
+			}
+		case propertyCSVMetadata:
+			var csvMetadata struct {
+				MinKubeVersion string `json:"minKubeVersion,omitempty"`
+			}
+			if err := json.Unmarshal(prop.Value, &csvMetadata); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s property: %w", propertyCSVMetadata, err))
+			}
+		}
+	}
+	if !foundPackage {
+		errs = append(errs, fmt.Errorf("missing %s property", property.TypePackage))
+	}
+	if !inChannel {
+		errs = append(errs, errors.New("not an entry of any channel of its package"))
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// reportInvalidBundles publishes the number of invalid bundles of each
+// catalog, and logs and records an event for each bundle whose error has not
+// been reported yet.
+func (es *catalogdEntitySource) reportInvalidBundles(ctx context.Context, catalogs map[string]string, invalid []invalidBundle) {
+	counts := map[string]float64{}
+	for catalog := range catalogs {
+		counts[catalog] = 0
+	}
+	for _, b := range invalid {
+		counts[b.bundle.Spec.Catalog.Name]++
+	}
+	invalidBundles.Reset()
+	for catalog, count := range counts {
+		invalidBundles.WithLabelValues(catalog).Set(count)
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	reported := make(map[string]string, len(invalid))
+	for _, b := range invalid {
+		msg := b.err.Error()
+		reported[b.bundle.Name] = msg
+		if es.reported[b.bundle.Name] == msg {
+			continue
+		}
+		catalog := b.bundle.Spec.Catalog.Name
+		log.FromContext(ctx).Info("skipping invalid bundle", "catalog", catalog, "package", b.bundle.Spec.Package, "bundle", b.bundle.Name, "error", msg)
+		if es.recorder != nil {
+			ref := &catalogd.Catalog{}
+			ref.SetName(catalog)
+			ref.SetGroupVersionKind(catalogd.GroupVersion.WithKind("Catalog"))
+			es.recorder.Eventf(ref, corev1.EventTypeWarning, ReasonInvalidBundleMetadata, "bundle %q of package %q skipped: %s", b.bundle.Name, b.bundle.Spec.Package, msg)
+		}
+	}
+	es.reported = reported
+}
+
+// channelEntry is an entry of the named channel.
+type channelEntry struct {
+	catalogd.ChannelEntry
+	channel string
+}
+
+// channelEntriesByBundle indexes the channel entries of each package by the
+// name of the BundleMetadata they refer to, which is scoped by catalog, so
+// that a bundle's entries are found without scanning its package's channels.
+func channelEntriesByBundle(packages map[string]catalogd.Package) map[string]map[string][]channelEntry {
+	entries := map[string]map[string][]channelEntry{}
+	for pkgName, pkg := range packages {
+		byBundle := map[string][]channelEntry{}
+		for _, ch := range pkg.Spec.Channels {
+			for _, e := range ch.Entries {
+				name := fmt.Sprintf("%s-%s", pkg.Spec.Catalog.Name, e.Name)
+				byBundle[name] = append(byBundle[name], channelEntry{ChannelEntry: e, channel: ch.Name})
+			}
+		}
+		entries[pkgName] = byBundle
+	}
+	return entries
+}
+
+// fetchMetadata returns the bundle metadata, the package metadata keyed by name, and
+// the resolved image reference of each catalog keyed by catalog name.
+func fetchMetadata(ctx context.Context, client client.Client) (catalogd.BundleMetadataList, map[string]catalogd.Package, map[string]string, error) {
 	packageMetdatas := catalogd.PackageList{}
 	if err := client.List(ctx, &packageMetdatas); err != nil {
-		return catalogd.BundleMetadataList{}, nil, err
+		return catalogd.BundleMetadataList{}, nil, nil, err
 	}
 	bundleMetadatas := catalogd.BundleMetadataList{}
 	if err := client.List(ctx, &bundleMetadatas); err != nil {
-		return catalogd.BundleMetadataList{}, nil, err
+		return catalogd.BundleMetadataList{}, nil, nil, err
+	}
+	catalogList := catalogd.CatalogList{}
+	if err := client.List(ctx, &catalogList); err != nil {
+		return catalogd.BundleMetadataList{}, nil, nil, err
 	}
 	packages := map[string]catalogd.Package{}
 	for _, pkg := range packageMetdatas.Items {
 		packages[pkg.Name] = pkg
 	}
-	return bundleMetadatas, packages, nil
+	catalogs := map[string]string{}
+	for _, catalog := range catalogList.Items {
+		if catalog.Status.ResolvedSource != nil && catalog.Status.ResolvedSource.Image != nil {
+			catalogs[catalog.Name] = catalog.Status.ResolvedSource.Image.Ref
+		}
+	}
+	return bundleMetadatas, packages, catalogs, nil
 }
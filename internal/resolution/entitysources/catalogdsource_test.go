@@ -0,0 +1,114 @@
+package entitysources_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/operator-framework/deppy/pkg/deppy/input"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/entitysources"
+)
+
+func TestEntitySources(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EntitySources Suite")
+}
+
+func bundleMetadata(name, pkg string, props ...catalogd.Property) *catalogd.BundleMetadata {
+	return &catalogd.BundleMetadata{
+		ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-" + name},
+		Spec: catalogd.BundleMetadataSpec{
+			Catalog:    corev1.LocalObjectReference{Name: "operatorhub"},
+			Package:    pkg,
+			Image:      "quay.io/operatorhub/" + name,
+			Properties: props,
+		},
+	}
+}
+
+var _ = Describe("CatalogdEntitySource", func() {
+	var (
+		objects  []client.Object
+		recorder *record.FakeRecorder
+	)
+	BeforeEach(func() {
+		objects = []client.Object{
+			&catalogd.Catalog{ObjectMeta: metav1.ObjectMeta{Name: "operatorhub"}},
+			&catalogd.Package{
+				ObjectMeta: metav1.ObjectMeta{Name: "operatorhub-prometheus"},
+				Spec: catalogd.PackageSpec{
+					Catalog: corev1.LocalObjectReference{Name: "operatorhub"},
+					Name:    "prometheus",
+					Channels: []catalogd.PackageChannel{{
+						Name: "beta",
+						Entries: []catalogd.ChannelEntry{
+							{Name: "prometheus.v0.37.0"},
+							{Name: "prometheus.v0.47.0", Replaces: "prometheus.v0.37.0"},
+						},
+					}},
+				},
+			},
+			bundleMetadata("prometheus.v0.37.0", "prometheus",
+				catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"0.37.0"}`)},
+			),
+		}
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	entities := func() input.EntityList {
+		scheme := runtime.NewScheme()
+		Expect(catalogd.AddToScheme(scheme)).To(Succeed())
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		es := entitysources.NewCatalogdEntitySource(cl).WithEventRecorder(recorder)
+		list, err := es.Filter(context.Background(), func(*input.Entity) bool { return true })
+		Expect(err).NotTo(HaveOccurred())
+		return list
+	}
+
+	It("returns an entity for each channel entry of a valid bundle", func() {
+		list := entities()
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Properties).To(HaveKeyWithValue("olm.bundle.name", `"prometheus.v0.37.0"`))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("skips bundles with malformed properties and records an event on their catalog", func() {
+		objects = append(objects, bundleMetadata("prometheus.v0.47.0", "prometheus",
+			catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"0.47.0"}`)},
+			catalogd.Property{Type: "olm.csv.metadata", Value: []byte(`"not an object"`)},
+		))
+		list := entities()
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Properties).To(HaveKeyWithValue("olm.bundle.name", `"prometheus.v0.37.0"`))
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring(entitysources.ReasonInvalidBundleMetadata),
+			ContainSubstring(`bundle "operatorhub-prometheus.v0.47.0" of package "prometheus" skipped: invalid olm.csv.metadata property`),
+		)))
+	})
+
+	It("skips bundles that are not an entry of any channel", func() {
+		objects = append(objects, bundleMetadata("prometheus.v0.50.0", "prometheus",
+			catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"0.50.0"}`)},
+		))
+		Expect(entities()).To(HaveLen(1))
+		Expect(recorder.Events).To(Receive(ContainSubstring("not an entry of any channel of its package")))
+	})
+
+	It("skips bundles with an invalid version", func() {
+		objects = append(objects, bundleMetadata("prometheus.v0.47.0", "prometheus",
+			catalogd.Property{Type: "olm.package", Value: []byte(`{"packageName":"prometheus","version":"latest"}`)},
+		))
+		Expect(entities()).To(HaveLen(1))
+		Expect(recorder.Events).To(Receive(ContainSubstring(`invalid version "latest" in olm.package property`)))
+	})
+})
@@ -0,0 +1,115 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgradepath finds chains of upgrade edges between the bundles of a
+// catalog channel.
+package upgradepath
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver/v4"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+)
+
+// NoPathError is returned when a channel has no chain of upgrade edges from
+// one bundle to another.
+type NoPathError struct {
+	Channel string
+	From    string
+	To      string
+}
+
+func (e *NoPathError) Error() string {
+	return fmt.Sprintf("no upgrade path from %q to %q in channel %q", e.From, e.To, e.Channel)
+}
+
+// UpgradesFrom returns true if entry is a direct upgrade from the bundle named
+// from, because it replaces or skips it or fromVersion is in its skip range.
+// Skip ranges are not considered if fromVersion is nil.
+func UpgradesFrom(entry catalogd.ChannelEntry, from string, fromVersion *semver.Version) bool {
+	if entry.Name == from {
+		return false
+	}
+	if entry.Replaces == from {
+		return true
+	}
+	for _, skip := range entry.Skips {
+		if skip == from {
+			return true
+		}
+	}
+	if entry.SkipRange != "" && fromVersion != nil {
+		if skipRange, err := semver.ParseRange(entry.SkipRange); err == nil && skipRange(*fromVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the shortest chain of bundles in channel that upgrades from the
+// bundle named from to the bundle named to. The chain starts with the first
+// bundle to upgrade to and ends with to; it is empty if from and to are the
+// same bundle. versions holds the versions of the channel's bundles and is
+// used to evaluate skip ranges. Ties between chains of the same length are
+// broken by bundle name, so the result is stable.
+func Find(channel catalogd.PackageChannel, versions map[string]semver.Version, from, to string) ([]string, error) {
+	if from == to {
+		return nil, nil
+	}
+	entries := append([]catalogd.ChannelEntry{}, channel.Entries...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	found := false
+	for _, e := range entries {
+		found = found || e.Name == to
+	}
+	if !found {
+		return nil, fmt.Errorf("bundle %q is not in channel %q", to, channel.Name)
+	}
+
+	// Breadth-first search over the upgrade edges, starting at from.
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		var currentVersion *semver.Version
+		if v, ok := versions[current]; ok {
+			currentVersion = &v
+		}
+		for _, e := range entries {
+			if _, seen := previous[e.Name]; seen || !UpgradesFrom(e, current, currentVersion) {
+				continue
+			}
+			previous[e.Name] = current
+			if e.Name == to {
+				return chainTo(previous, from, to), nil
+			}
+			queue = append(queue, e.Name)
+		}
+	}
+	return nil, &NoPathError{Channel: channel.Name, From: from, To: to}
+}
+
+func chainTo(previous map[string]string, from, to string) []string {
+	var chain []string
+	for name := to; name != from; name = previous[name] {
+		chain = append([]string{name}, chain...)
+	}
+	return chain
+}
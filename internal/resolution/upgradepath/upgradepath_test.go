@@ -0,0 +1,77 @@
+package upgradepath_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/upgradepath"
+)
+
+func TestUpgradePath(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "UpgradePath Suite")
+}
+
+var _ = Describe("Find", func() {
+	var (
+		channel  catalogd.PackageChannel
+		versions map[string]semver.Version
+	)
+	BeforeEach(func() {
+		channel = catalogd.PackageChannel{
+			Name: "stable",
+			Entries: []catalogd.ChannelEntry{
+				{Name: "op.v1.0.0"},
+				{Name: "op.v1.1.0", Replaces: "op.v1.0.0"},
+				{Name: "op.v1.2.0", Replaces: "op.v1.1.0"},
+				{Name: "op.v2.0.0", Replaces: "op.v1.2.0", SkipRange: ">=1.0.0 <1.2.0"},
+				{Name: "op.v2.1.0", Replaces: "op.v2.0.0", Skips: []string{"op.v1.2.0"}},
+			},
+		}
+		versions = map[string]semver.Version{
+			"op.v1.0.0": semver.MustParse("1.0.0"),
+			"op.v1.1.0": semver.MustParse("1.1.0"),
+			"op.v1.2.0": semver.MustParse("1.2.0"),
+			"op.v2.0.0": semver.MustParse("2.0.0"),
+			"op.v2.1.0": semver.MustParse("2.1.0"),
+		}
+	})
+
+	It("returns an empty path for the same bundle", func() {
+		path, err := upgradepath.Find(channel, versions, "op.v1.0.0", "op.v1.0.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(BeEmpty())
+	})
+	It("returns a direct successor", func() {
+		path, err := upgradepath.Find(channel, versions, "op.v1.0.0", "op.v1.1.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal([]string{"op.v1.1.0"}))
+	})
+	It("uses skip ranges and skips to find the shortest path", func() {
+		path, err := upgradepath.Find(channel, versions, "op.v1.0.0", "op.v2.1.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal([]string{"op.v2.0.0", "op.v2.1.0"}))
+
+		path, err = upgradepath.Find(channel, versions, "op.v1.2.0", "op.v2.1.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal([]string{"op.v2.1.0"}))
+	})
+	It("ignores skip ranges for bundles without a known version", func() {
+		path, err := upgradepath.Find(channel, nil, "op.v1.0.0", "op.v2.0.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path).To(Equal([]string{"op.v1.1.0", "op.v1.2.0", "op.v2.0.0"}))
+	})
+	It("fails if there is no path", func() {
+		_, err := upgradepath.Find(channel, versions, "op.v2.0.0", "op.v1.0.0")
+		Expect(err).To(MatchError(&upgradepath.NoPathError{Channel: "stable", From: "op.v2.0.0", To: "op.v1.0.0"}))
+	})
+	It("fails if the target is not in the channel", func() {
+		_, err := upgradepath.Find(channel, versions, "op.v1.0.0", "op.v3.0.0")
+		Expect(err).To(MatchError(`bundle "op.v3.0.0" is not in channel "stable"`))
+	})
+})
@@ -49,7 +49,8 @@ func NewBundleVariable(bundleEntity *olmentity.BundleEntity, dependencyBundleEnt
 var _ input.VariableSource = &BundlesAndDepsVariableSource{}
 
 type BundlesAndDepsVariableSource struct {
-	variableSources []input.VariableSource
+	variableSources      []input.VariableSource
+	dependencyPredicates []input.Predicate
 }
 
 func NewBundlesAndDepsVariableSource(inputVariableSources ...input.VariableSource) *BundlesAndDepsVariableSource {
@@ -58,6 +59,13 @@ func NewBundlesAndDepsVariableSource(inputVariableSources ...input.VariableSourc
 	}
 }
 
+// WithDependencyPredicates restricts the bundles that satisfy dependencies to
+// the ones that match all of predicates.
+func (b *BundlesAndDepsVariableSource) WithDependencyPredicates(predicates ...input.Predicate) *BundlesAndDepsVariableSource {
+	b.dependencyPredicates = append(b.dependencyPredicates, predicates...)
+	return b
+}
+
 func (b *BundlesAndDepsVariableSource) GetVariables(ctx context.Context, entitySource input.EntitySource) ([]deppy.Variable, error) {
 	var variables []deppy.Variable
 
@@ -120,7 +128,7 @@ func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context
 		if err != nil {
 			return nil, err
 		}
-		packageDependencyBundles, err := entitySource.Filter(ctx, input.And(predicates.WithPackageName(requiredPackage.PackageName), predicates.InSemverRange(semverRange)))
+		packageDependencyBundles, err := entitySource.Filter(ctx, input.And(append([]input.Predicate{predicates.WithPackageName(requiredPackage.PackageName), predicates.InSemverRange(semverRange)}, b.dependencyPredicates...)...))
 		if err != nil {
 			return nil, err
 		}
@@ -141,7 +149,7 @@ func (b *BundlesAndDepsVariableSource) getEntityDependencies(ctx context.Context
 	gvkDependencies, _ := bundleEntity.RequiredGVKs()
 	for i := 0; i < len(gvkDependencies); i++ {
 		providedGvk := gvkDependencies[i].AsGVK()
-		gvkDependencyBundles, err := entitySource.Filter(ctx, predicates.ProvidesGVK(&providedGvk))
+		gvkDependencyBundles, err := entitySource.Filter(ctx, input.And(append([]input.Predicate{predicates.ProvidesGVK(&providedGvk)}, b.dependencyPredicates...)...))
 		if err != nil {
 			return nil, err
 		}
@@ -25,6 +25,26 @@ const (
 
 // ----
 
+// PropertyBundleCatalog is populated by entity sources with the catalog an
+// entity was sourced from.
+const PropertyBundleCatalog = "olm.bundle.catalog"
+
+// Catalog identifies the catalog, and the snapshot of its content, that a
+// bundle entity was sourced from.
+type Catalog struct {
+	Name string `json:"name"`
+	// Ref is the resolved image reference of the catalog content, if known.
+	Ref string `json:"ref,omitempty"`
+}
+
+// PropertyBundleName is populated by entity sources with the name of the
+// bundle in its catalog, which is how channel entries refer to bundles.
+const PropertyBundleName = "olm.bundle.name"
+
+// PropertyBundleMinKubeVersion is populated by entity sources with the minimum
+// Kubernetes version the bundle supports, if it declares one.
+const PropertyBundleMinKubeVersion = "olm.bundle.minKubeVersion"
+
 type ChannelProperties struct {
 	property.Channel
 	Replaces  string   `json:"replaces,omitempty"`
@@ -72,6 +92,9 @@ type BundleEntity struct {
 	semVersion        *semver.Version
 	bundlePath        string
 	mediaType         string
+	catalog           *Catalog
+	bundleName        string
+	minKubeVersion    *semver.Version
 	mu                sync.RWMutex
 }
 
@@ -146,6 +169,29 @@ func (b *BundleEntity) MediaType() (string, error) {
 	return b.mediaType, nil
 }
 
+func (b *BundleEntity) Catalog() (*Catalog, error) {
+	if err := b.loadCatalog(); err != nil {
+		return nil, err
+	}
+	return b.catalog, nil
+}
+
+func (b *BundleEntity) BundleName() (string, error) {
+	if err := b.loadBundleName(); err != nil {
+		return "", err
+	}
+	return b.bundleName, nil
+}
+
+// MinKubeVersion returns the minimum Kubernetes version the bundle supports,
+// or nil if it doesn't declare one.
+func (b *BundleEntity) MinKubeVersion() (*semver.Version, error) {
+	if err := b.loadMinKubeVersion(); err != nil {
+		return nil, err
+	}
+	return b.minKubeVersion, nil
+}
+
 func (b *BundleEntity) loadMediaType() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -251,6 +297,52 @@ func (b *BundleEntity) loadBundlePath() error {
 	return nil
 }
 
+func (b *BundleEntity) loadCatalog() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.catalog == nil {
+		catalog, err := loadFromEntity[Catalog](b.Entity, PropertyBundleCatalog, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle catalog for entity '%s': %w", b.ID, err)
+		}
+		b.catalog = &catalog
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadBundleName() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.bundleName == "" {
+		bundleName, err := loadFromEntity[string](b.Entity, PropertyBundleName, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle name for entity '%s': %w", b.ID, err)
+		}
+		b.bundleName = bundleName
+	}
+	return nil
+}
+
+func (b *BundleEntity) loadMinKubeVersion() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.minKubeVersion == nil {
+		minKubeVersion, err := loadFromEntity[string](b.Entity, PropertyBundleMinKubeVersion, optional)
+		if err != nil {
+			return fmt.Errorf("error determining bundle min kube version for entity '%s': %w", b.ID, err)
+		}
+		if minKubeVersion == "" {
+			return nil
+		}
+		semVer, err := semver.ParseTolerant(minKubeVersion)
+		if err != nil {
+			return fmt.Errorf("could not parse min kube version (%s) for entity '%s': %w", minKubeVersion, b.ID, err)
+		}
+		b.minKubeVersion = &semVer
+	}
+	return nil
+}
+
 func loadFromEntity[T interface{}](entity *input.Entity, propertyName string, required propertyRequirement) (T, error) {
 	deserializedProperty := *new(T)
 	propertyValue, ok := entity.Properties[propertyName]
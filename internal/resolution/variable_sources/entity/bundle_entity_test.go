@@ -296,4 +296,86 @@ var _ = Describe("BundleEntity", func() {
 			Expect(err.Error()).To(Equal("error determining bundle mediatype for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.mediatype' ('badtype') could not be parsed: invalid character 'b' looking for beginning of value"))
 		})
 	})
+
+	Describe("Catalog", func() {
+		It("should return the bundle catalog property if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleCatalog: `{"name":"operatorhub","ref":"quay.io/operatorhubio/catalog@sha256:abc"}`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			catalog, err := bundleEntity.Catalog()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*catalog).To(Equal(olmentity.Catalog{Name: "operatorhub", Ref: "quay.io/operatorhubio/catalog@sha256:abc"}))
+		})
+		It("should not return an error if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			catalog, err := bundleEntity.Catalog()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*catalog).To(Equal(olmentity.Catalog{}))
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleCatalog: "badcatalog",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			catalog, err := bundleEntity.Catalog()
+			Expect(catalog).To(BeNil())
+			Expect(err.Error()).To(Equal("error determining bundle catalog for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.catalog' ('badcatalog') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+	Describe("BundleName", func() {
+		It("should return the bundle name property if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleName: `"prometheusoperator.0.14.0"`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleName).To(Equal("prometheusoperator.0.14.0"))
+		})
+		It("should not return an error if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(bundleName).To(BeEmpty())
+		})
+		It("should return error if the property is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleName: "badname",
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			bundleName, err := bundleEntity.BundleName()
+			Expect(bundleName).To(BeEmpty())
+			Expect(err.Error()).To(Equal("error determining bundle name for entity 'operatorhub/prometheus/0.14.0': property 'olm.bundle.name' ('badname') could not be parsed: invalid character 'b' looking for beginning of value"))
+		})
+	})
+	Describe("MinKubeVersion", func() {
+		It("should return the bundle min kube version if present", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleMinKubeVersion: `"v1.26"`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			minKubeVersion, err := bundleEntity.MinKubeVersion()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*minKubeVersion).To(Equal(semver.MustParse("1.26.0")))
+		})
+		It("should not return an error if the property is not found", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			minKubeVersion, err := bundleEntity.MinKubeVersion()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(minKubeVersion).To(BeNil())
+		})
+		It("should return error if the version is malformed", func() {
+			entity := input.NewEntity("operatorhub/prometheus/0.14.0", map[string]string{
+				olmentity.PropertyBundleMinKubeVersion: `"latest"`,
+			})
+			bundleEntity := olmentity.NewBundleEntity(entity)
+			minKubeVersion, err := bundleEntity.MinKubeVersion()
+			Expect(minKubeVersion).To(BeNil())
+			Expect(err.Error()).To(ContainSubstring("could not parse min kube version (latest) for entity 'operatorhub/prometheus/0.14.0'"))
+		})
+	})
 })
@@ -2,27 +2,79 @@ package olm
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy"
 	"github.com/operator-framework/deppy/pkg/deppy/input"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/internal/defaults"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/bundles_and_dependencies"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/crd_constraints"
+	olmentity "github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/required_package"
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/util/predicates"
 )
 
 var _ input.VariableSource = &OLMVariableSource{}
 
 type OLMVariableSource struct {
-	client client.Client
+	client           client.Client
+	kubeVersion      func(context.Context) (semver.Version, error)
+	upgradeTarget    *semver.Version
+	bundlePredicates []namedPredicate
 }
 
-func NewOLMVariableSource(cl client.Client) *OLMVariableSource {
-	return &OLMVariableSource{
+type namedPredicate struct {
+	name      string
+	predicate input.Predicate
+}
+
+type Option func(*OLMVariableSource)
+
+// WithKubeVersion restricts resolution to bundles that support the cluster's
+// Kubernetes version. If upgradeTarget is not nil, bundles that only support
+// the version the cluster is being upgraded to are reported as available
+// after the upgrade.
+func WithKubeVersion(kubeVersion semver.Version, upgradeTarget *semver.Version) Option {
+	return WithKubeVersionFunc(func(context.Context) (semver.Version, error) {
+		return kubeVersion, nil
+	}, upgradeTarget)
+}
+
+// WithKubeVersionFunc is like WithKubeVersion, but the cluster's Kubernetes
+// version is read with kubeVersion every time variables are built, so that it
+// can change while the controller runs.
+func WithKubeVersionFunc(kubeVersion func(context.Context) (semver.Version, error), upgradeTarget *semver.Version) Option {
+	return func(o *OLMVariableSource) {
+		o.kubeVersion = kubeVersion
+		o.upgradeTarget = upgradeTarget
+	}
+}
+
+// WithBundlePredicate restricts resolution to bundles that match predicate,
+// both for the packages Operators require and for their dependencies. It lets
+// distributions add their own policies, e.g. only resolving FIPS-compliant
+// bundles. name describes the predicate in resolution errors.
+func WithBundlePredicate(name string, predicate input.Predicate) Option {
+	return func(o *OLMVariableSource) {
+		o.bundlePredicates = append(o.bundlePredicates, namedPredicate{name: name, predicate: predicate})
+	}
+}
+
+func NewOLMVariableSource(cl client.Client, options ...Option) *OLMVariableSource {
+	o := &OLMVariableSource{
 		client: cl,
 	}
+	for _, option := range options {
+		option(o)
+	}
+	return o
 }
 
 func (o *OLMVariableSource) GetVariables(ctx context.Context, entitySource input.EntitySource) ([]deppy.Variable, error) {
@@ -30,16 +82,55 @@ func (o *OLMVariableSource) GetVariables(ctx context.Context, entitySource input
 	if err := o.client.List(ctx, &operatorList); err != nil {
 		return nil, err
 	}
+	if err := defaults.ApplyAll(ctx, o.client, operatorList.Items); err != nil {
+		return nil, err
+	}
+
+	var kubeVersion *semver.Version
+	if o.kubeVersion != nil {
+		v, err := o.kubeVersion(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading the cluster's kubernetes version: %w", err)
+		}
+		kubeVersion = &v
+	}
 
 	var inputVariableSources []input.VariableSource
 
 	// build required package variable sources
 	for _, operator := range operatorList.Items {
-		rps, err := required_package.NewRequiredPackage(
-			operator.Spec.PackageName,
+		var catalogName, catalogRef string
+		if operator.Spec.Catalog != nil {
+			catalogName, catalogRef = operator.Spec.Catalog.Name, operator.Spec.Catalog.Ref
+		}
+		channels := make([]string, 0, len(operator.Spec.Channels))
+		for _, channel := range operator.Spec.Channels {
+			channels = append(channels, string(channel))
+		}
+		options := []required_package.RequiredPackageOption{
 			required_package.InVersionRange(operator.Spec.Version),
+			required_package.PreferVersionRanges(operator.Spec.VersionPreference...),
+			required_package.ExcludingVersions(operator.Spec.VersionExcludes...),
 			required_package.InChannel(operator.Spec.Channel),
-		)
+			required_package.InChannels(channels...),
+			required_package.InCatalog(catalogName, catalogRef),
+		}
+		if operator.Spec.UpgradePolicy == operatorsv1alpha1.UpgradePolicyZStream && operator.Spec.Version == "" && len(operator.Spec.VersionPreference) == 0 {
+			installed, err := o.installedVersion(ctx, entitySource, &operator)
+			if err != nil {
+				return nil, err
+			}
+			if installed != nil {
+				options = append(options, required_package.InZStreamOf(*installed))
+			}
+		}
+		for _, p := range o.bundlePredicates {
+			options = append(options, required_package.WithPredicate(p.name, p.predicate))
+		}
+		if kubeVersion != nil {
+			options = append(options, required_package.CompatibleWithKubeVersion(*kubeVersion, o.upgradeTarget))
+		}
+		rps, err := required_package.NewRequiredPackage(operator.Spec.PackageName, options...)
 		if err != nil {
 			return nil, err
 		}
@@ -47,6 +138,38 @@ func (o *OLMVariableSource) GetVariables(ctx context.Context, entitySource input
 	}
 
 	// build variable source pipeline
-	variableSource := crd_constraints.NewCRDUniquenessConstraintsVariableSource(bundles_and_dependencies.NewBundlesAndDepsVariableSource(inputVariableSources...))
+	bundlesAndDeps := bundles_and_dependencies.NewBundlesAndDepsVariableSource(inputVariableSources...)
+	if kubeVersion != nil {
+		bundlesAndDeps.WithDependencyPredicates(predicates.CompatibleWithKubeVersion(*kubeVersion))
+	}
+	for _, p := range o.bundlePredicates {
+		bundlesAndDeps.WithDependencyPredicates(p.predicate)
+	}
+	variableSource := crd_constraints.NewCRDUniquenessConstraintsVariableSource(bundlesAndDeps)
 	return variableSource.GetVariables(ctx, entitySource)
 }
+
+// installedVersion returns the version of the bundle the Operator's
+// BundleDeployment installs, or nil if there is none, the Operator may not
+// manage it, or it is not in entitySource.
+func (o *OLMVariableSource) installedVersion(ctx context.Context, entitySource input.EntitySource, operator *operatorsv1alpha1.Operator) (*semver.Version, error) {
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := o.client.Get(ctx, types.NamespacedName{Name: operator.GetName()}, bd); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	if owner := metav1.GetControllerOf(bd); owner != nil && owner.UID != operator.GetUID() ||
+		owner == nil && operator.Spec.AdoptionPolicy != operatorsv1alpha1.AdoptionPolicyAdopt {
+		return nil, nil
+	}
+	if bd.Spec.Template == nil || bd.Spec.Template.Spec.Source.Image == nil {
+		return nil, nil
+	}
+	installed, err := entitySource.Filter(ctx, input.And(
+		predicates.WithPackageName(operator.Spec.PackageName),
+		predicates.WithBundlePath(bd.Spec.Template.Spec.Source.Image.Ref),
+	))
+	if err != nil || len(installed) == 0 {
+		return nil, err
+	}
+	return olmentity.NewBundleEntity(&installed[0]).Version()
+}
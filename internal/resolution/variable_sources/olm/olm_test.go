@@ -17,9 +17,11 @@ import (
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/crd_constraints"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/olm"
 	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/required_package"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -27,6 +29,7 @@ import (
 func FakeClient(objects ...client.Object) client.Client {
 	scheme := runtime.NewScheme()
 	utilruntime.Must(v1alpha1.AddToScheme(scheme))
+	utilruntime.Must(rukpakv1alpha1.AddToScheme(scheme))
 	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
 }
 
@@ -70,6 +73,13 @@ func withVersionRange(versionRange string) opOption {
 	}
 }
 
+func withUpgradePolicy(policy operatorsv1alpha1.UpgradePolicy) opOption {
+	return func(op *operatorsv1alpha1.Operator) error {
+		op.Spec.UpgradePolicy = policy
+		return nil
+	}
+}
+
 func operator(name string, opts ...opOption) *operatorsv1alpha1.Operator {
 	op := operatorsv1alpha1.Operator{
 		ObjectMeta: metav1.ObjectMeta{
@@ -160,6 +170,83 @@ var _ = Describe("OLMVariableSource", func() {
 		})))
 	})
 
+	It("should only produce z-stream upgrades of the installed bundle for the ZStream upgrade policy", func() {
+		op := operator("prometheus", withUpgradePolicy(operatorsv1alpha1.UpgradePolicyZStream))
+		op.UID = "prometheus-uid"
+		installed := &rukpakv1alpha1.BundleDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "prometheus",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: operatorsv1alpha1.GroupVersion.String(),
+					Kind:       "Operator",
+					Name:       op.Name,
+					UID:        op.UID,
+					Controller: pointer.Bool(true),
+				}},
+			},
+			Spec: rukpakv1alpha1.BundleDeploymentSpec{
+				Template: &rukpakv1alpha1.BundleTemplate{
+					Spec: rukpakv1alpha1.BundleSpec{
+						Source: rukpakv1alpha1.BundleSource{
+							Type:  rukpakv1alpha1.SourceTypeImage,
+							Image: &rukpakv1alpha1.ImageSource{Ref: "quay.io/operatorhubio/prometheus@sha256:3e281e587de3d03011440685fc4fb782672beab044c1ebadc42788ce05a21c35"},
+						},
+					},
+				},
+			},
+		}
+		cl := FakeClient(op, installed)
+
+		olmVariableSource := olm.NewOLMVariableSource(cl)
+		variables, err := olmVariableSource.GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+
+		packageRequiredVariables := filterVariables[*required_package.RequiredPackageVariable](variables)
+		Expect(packageRequiredVariables).To(HaveLen(1))
+		Expect(packageRequiredVariables[0].BundleEntities()).To(HaveLen(1))
+		Expect(packageRequiredVariables[0].BundleEntities()[0].Entity).To(Equal(entityFromCache("operatorhub/prometheus/0.37.0")))
+
+		By("applying every upgrade before a bundle is installed")
+		cl = FakeClient(operator("prometheus", withUpgradePolicy(operatorsv1alpha1.UpgradePolicyZStream)))
+		variables, err = olm.NewOLMVariableSource(cl).GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+		packageRequiredVariables = filterVariables[*required_package.RequiredPackageVariable](variables)
+		Expect(packageRequiredVariables[0].BundleEntities()).To(HaveLen(2))
+
+		By("ignoring a BundleDeployment the Operator does not control")
+		installed.OwnerReferences = nil
+		cl = FakeClient(op, installed)
+		variables, err = olm.NewOLMVariableSource(cl).GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+		packageRequiredVariables = filterVariables[*required_package.RequiredPackageVariable](variables)
+		Expect(packageRequiredVariables[0].BundleEntities()).To(HaveLen(2))
+	})
+
+	It("should only produce bundles that match the bundle predicates", func() {
+		cl := FakeClient(operator("prometheus"))
+
+		notVersion := func(version string) input.Predicate {
+			return func(entity *input.Entity) bool {
+				return !strings.Contains(entity.Properties["olm.package"], version)
+			}
+		}
+		olmVariableSource := olm.NewOLMVariableSource(cl, olm.WithBundlePredicate("not 0.47.0", notVersion("0.47.0")))
+		variables, err := olmVariableSource.GetVariables(context.Background(), testEntitySource)
+		Expect(err).ToNot(HaveOccurred())
+
+		bundleVariables := filterVariables[*bundles_and_dependencies.BundleVariable](variables)
+		Expect(bundleVariables).To(HaveLen(1))
+		Expect(bundleVariables[0].BundleEntity().Entity).To(Equal(entityFromCache("operatorhub/prometheus/0.37.0")))
+
+		By("naming the predicates when no bundle matches")
+		olmVariableSource = olm.NewOLMVariableSource(cl,
+			olm.WithBundlePredicate("not 0.47.0", notVersion("0.47.0")),
+			olm.WithBundlePredicate("not 0.37.0", notVersion("0.37.0")),
+		)
+		_, err = olmVariableSource.GetVariables(context.Background(), testEntitySource)
+		Expect(err).To(MatchError("package 'prometheus' matching 'not 0.47.0', 'not 0.37.0' not found"))
+	})
+
 	It("should produce GlobalConstraints variables", func() {
 		cl := FakeClient(operator("prometheus"), operator("packageA"))
 
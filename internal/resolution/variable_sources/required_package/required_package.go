@@ -3,6 +3,8 @@ package required_package
 import (
 	"context"
 	"fmt"
+	gosort "sort"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/operator-framework/deppy/pkg/deppy"
@@ -16,13 +18,20 @@ import (
 
 type RequiredPackageVariable struct {
 	*input.SimpleVariable
-	bundleEntities []*olmentity.BundleEntity
+	bundleEntities               []*olmentity.BundleEntity
+	availableAfterClusterUpgrade []*olmentity.BundleEntity
 }
 
 func (r *RequiredPackageVariable) BundleEntities() []*olmentity.BundleEntity {
 	return r.bundleEntities
 }
 
+// AvailableAfterClusterUpgrade returns the bundles of the package that the
+// cluster's Kubernetes version doesn't support yet but its upgrade target does.
+func (r *RequiredPackageVariable) AvailableAfterClusterUpgrade() []*olmentity.BundleEntity {
+	return r.availableAfterClusterUpgrade
+}
+
 func NewRequiredPackageVariable(packageName string, bundleEntities []*olmentity.BundleEntity) *RequiredPackageVariable {
 	id := deppy.IdentifierFromString(fmt.Sprintf("required package %s", packageName))
 	var entityIDs []deppy.Identifier
@@ -54,6 +63,45 @@ func InVersionRange(versionRange string) RequiredPackageOption {
 	}
 }
 
+// PreferVersionRanges restricts the package to bundles whose version is in one
+// of versionRanges. Bundles in an earlier range are preferred over bundles in a
+// later one, whatever their version.
+func PreferVersionRanges(versionRanges ...string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		if len(versionRanges) == 0 {
+			return nil
+		}
+		var inAnyRange []input.Predicate
+		for _, versionRange := range versionRanges {
+			vr, err := semver.ParseRange(versionRange)
+			if err != nil {
+				return fmt.Errorf("invalid version range '%s': %v", versionRange, err)
+			}
+			r.preferredRanges = append(r.preferredRanges, vr)
+			inAnyRange = append(inAnyRange, predicates.InSemverRange(vr))
+		}
+		r.versionPreference = versionRanges
+		r.predicates = append(r.predicates, input.Or(inAnyRange...))
+		return nil
+	}
+}
+
+// ExcludingVersions restricts the package to bundles whose version is in none
+// of versionRanges.
+func ExcludingVersions(versionRanges ...string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		for _, versionRange := range versionRanges {
+			vr, err := semver.ParseRange(versionRange)
+			if err != nil {
+				return fmt.Errorf("invalid version range '%s': %v", versionRange, err)
+			}
+			r.excludedRanges = append(r.excludedRanges, predicates.InSemverRange(vr))
+		}
+		r.versionExcludes = append(r.versionExcludes, versionRanges...)
+		return nil
+	}
+}
+
 func InChannel(channelName string) RequiredPackageOption {
 	return func(r *RequiredPackageVariableSource) error {
 		if channelName != "" {
@@ -64,11 +112,87 @@ func InChannel(channelName string) RequiredPackageOption {
 	}
 }
 
+// InChannels restricts the package to bundles from one of channelNames.
+// Bundles from an earlier channel are preferred over bundles from a later one.
+func InChannels(channelNames ...string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		if len(channelNames) == 0 {
+			return nil
+		}
+		var inAnyChannel []input.Predicate
+		for _, channelName := range channelNames {
+			inAnyChannel = append(inAnyChannel, predicates.InChannel(channelName))
+		}
+		r.channelNames = channelNames
+		r.predicates = append(r.predicates, input.Or(inAnyChannel...))
+		return nil
+	}
+}
+
+// InCatalog restricts the package to bundles sourced from the named catalog
+// and, if catalogRef is not empty, from that snapshot of its content.
+func InCatalog(catalogName, catalogRef string) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		if catalogName == "" && catalogRef != "" {
+			return fmt.Errorf("catalog ref '%s' requires a catalog name", catalogRef)
+		}
+		if catalogName != "" {
+			r.catalogName = catalogName
+			r.catalogRef = catalogRef
+			r.predicates = append(r.predicates, predicates.InCatalog(catalogName, catalogRef))
+		}
+		return nil
+	}
+}
+
+// InZStreamOf restricts the package to bundles with the major and minor
+// version of installed that are not older than it.
+func InZStreamOf(installed semver.Version) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		r.zStreamOf = &installed
+		r.predicates = append(r.predicates, predicates.InZStreamOf(installed))
+		return nil
+	}
+}
+
+// WithPredicate restricts the package to bundles that match predicate. name
+// describes the predicate in errors.
+func WithPredicate(name string, predicate input.Predicate) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		r.predicateNames = append(r.predicateNames, name)
+		r.predicates = append(r.predicates, predicate)
+		return nil
+	}
+}
+
+// CompatibleWithKubeVersion restricts the package to bundles that support the
+// cluster's Kubernetes version. If upgradeTarget is not nil, the bundles that
+// only the upgrade target supports are reported as available after the
+// cluster is upgraded.
+func CompatibleWithKubeVersion(kubeVersion semver.Version, upgradeTarget *semver.Version) RequiredPackageOption {
+	return func(r *RequiredPackageVariableSource) error {
+		r.kubeVersion = &kubeVersion
+		r.upgradeTarget = upgradeTarget
+		return nil
+	}
+}
+
 type RequiredPackageVariableSource struct {
-	packageName  string
-	versionRange string
-	channelName  string
-	predicates   []input.Predicate
+	packageName       string
+	versionRange      string
+	versionPreference []string
+	preferredRanges   []semver.Range
+	versionExcludes   []string
+	excludedRanges    []input.Predicate
+	channelName       string
+	channelNames      []string
+	catalogName       string
+	catalogRef        string
+	zStreamOf         *semver.Version
+	predicateNames    []string
+	kubeVersion       *semver.Version
+	upgradeTarget     *semver.Version
+	predicates        []input.Predicate
 }
 
 func NewRequiredPackage(packageName string, options ...RequiredPackageOption) (*RequiredPackageVariableSource, error) {
@@ -88,36 +212,210 @@ func NewRequiredPackage(packageName string, options ...RequiredPackageOption) (*
 }
 
 func (r *RequiredPackageVariableSource) GetVariables(ctx context.Context, entitySource input.EntitySource) ([]deppy.Variable, error) {
-	resultSet, err := entitySource.Filter(ctx, input.And(r.predicates...))
+	// Excluded versions are filtered separately from the other predicates,
+	// so that bundles that are only rejected for being excluded can be
+	// reported.
+	matching := input.And(r.predicates...)
+	if r.kubeVersion != nil {
+		matching = input.And(matching, predicates.CompatibleWithKubeVersion(*r.kubeVersion))
+	}
+	var notExcluded input.Predicate = func(*input.Entity) bool { return true }
+	if len(r.excludedRanges) > 0 {
+		notExcluded = input.Not(input.Or(r.excludedRanges...))
+	}
+	resultSet, err := entitySource.Filter(ctx, input.And(matching, notExcluded))
 	if err != nil {
 		return nil, err
 	}
+	var afterUpgradeSet input.EntityList
+	if r.kubeVersion != nil && r.upgradeTarget != nil {
+		afterUpgradeSet, err = entitySource.Filter(ctx, input.And(
+			input.And(r.predicates...),
+			notExcluded,
+			input.Not(predicates.CompatibleWithKubeVersion(*r.kubeVersion)),
+			predicates.CompatibleWithKubeVersion(*r.upgradeTarget),
+		))
+		if err != nil {
+			return nil, err
+		}
+	}
 	if len(resultSet) == 0 {
-		return nil, r.notFoundError()
+		notFound := r.notFoundError(len(afterUpgradeSet) > 0)
+		if len(r.excludedRanges) > 0 {
+			excluded, err := entitySource.Filter(ctx, matching)
+			if err != nil {
+				return nil, err
+			}
+			notFound.ExcludedVersions = versionsOf(excluded)
+		}
+		return nil, notFound
 	}
 	resultSet = resultSet.Sort(sort.ByChannelAndVersion)
+	if len(r.preferredRanges) > 0 {
+		gosort.SliceStable(resultSet, func(i, j int) bool {
+			return r.preferenceOf(&resultSet[i]) < r.preferenceOf(&resultSet[j])
+		})
+	}
+	// Channel preference takes precedence over version preference.
+	if len(r.channelNames) > 0 {
+		gosort.SliceStable(resultSet, func(i, j int) bool {
+			return r.channelPreferenceOf(&resultSet[i]) < r.channelPreferenceOf(&resultSet[j])
+		})
+	}
 	var bundleEntities []*olmentity.BundleEntity
 	for i := 0; i < len(resultSet); i++ {
 		bundleEntities = append(bundleEntities, olmentity.NewBundleEntity(&resultSet[i]))
 	}
-	return []deppy.Variable{
-		NewRequiredPackageVariable(r.packageName, bundleEntities),
-	}, nil
+	variable := NewRequiredPackageVariable(r.packageName, bundleEntities)
+	afterUpgradeSet = afterUpgradeSet.Sort(sort.ByChannelAndVersion)
+	for i := 0; i < len(afterUpgradeSet); i++ {
+		variable.availableAfterClusterUpgrade = append(variable.availableAfterClusterUpgrade, olmentity.NewBundleEntity(&afterUpgradeSet[i]))
+	}
+	return []deppy.Variable{variable}, nil
+}
+
+// preferenceOf returns the index of the first preferred version range that
+// contains the entity's version.
+func (r *RequiredPackageVariableSource) preferenceOf(entity *input.Entity) int {
+	version, err := olmentity.NewBundleEntity(entity).Version()
+	if err != nil {
+		return len(r.preferredRanges)
+	}
+	for i, vr := range r.preferredRanges {
+		if vr(*version) {
+			return i
+		}
+	}
+	return len(r.preferredRanges)
+}
+
+// channelPreferenceOf returns the index of the entity's channel in the
+// preferred channels.
+func (r *RequiredPackageVariableSource) channelPreferenceOf(entity *input.Entity) int {
+	channel, err := olmentity.NewBundleEntity(entity).ChannelName()
+	if err != nil {
+		return len(r.channelNames)
+	}
+	for i, name := range r.channelNames {
+		if name == channel {
+			return i
+		}
+	}
+	return len(r.channelNames)
+}
+
+func (r *RequiredPackageVariableSource) notFoundError(availableAfterClusterUpgrade bool) *PackageNotFoundError {
+	err := &PackageNotFoundError{
+		PackageName:       r.packageName,
+		VersionRange:      r.versionRange,
+		VersionPreference: r.versionPreference,
+		VersionExcludes:   r.versionExcludes,
+		Channel:           r.channelName,
+		Channels:          r.channelNames,
+		CatalogName:       r.catalogName,
+		CatalogRef:        r.catalogRef,
+	}
+	if r.zStreamOf != nil {
+		err.ZStreamOf = r.zStreamOf.String()
+	}
+	err.Predicates = r.predicateNames
+	if r.kubeVersion != nil {
+		err.KubeVersion = r.kubeVersion.String()
+	}
+	if availableAfterClusterUpgrade {
+		err.AvailableAfterClusterUpgradeTo = r.upgradeTarget.String()
+	}
+	return err
+}
+
+// versionsOf returns the distinct versions of entities, highest first.
+func versionsOf(entities input.EntityList) []string {
+	var versions []semver.Version
+	seen := map[string]bool{}
+	for i := range entities {
+		version, err := olmentity.NewBundleEntity(&entities[i]).Version()
+		if err != nil || seen[version.String()] {
+			continue
+		}
+		seen[version.String()] = true
+		versions = append(versions, *version)
+	}
+	gosort.Slice(versions, func(i, j int) bool { return versions[i].GT(versions[j]) })
+	var out []string
+	for _, v := range versions {
+		out = append(out, v.String())
+	}
+	return out
 }
 
-func (r *RequiredPackageVariableSource) notFoundError() error {
+// PackageNotFoundError is returned by GetVariables when no bundle matches the
+// required package and the constraints placed on it.
+type PackageNotFoundError struct {
+	PackageName       string
+	VersionRange      string
+	VersionPreference []string
+	VersionExcludes   []string
+	Channel           string
+	Channels          []string
+	CatalogName       string
+	CatalogRef        string
+	// ZStreamOf is the installed version bundles had to be a z-stream
+	// upgrade of, if any.
+	ZStreamOf string
+	// Predicates names the additional predicates bundles had to match.
+	Predicates []string
+	// KubeVersion is the Kubernetes version bundles had to support, if any.
+	KubeVersion string
+	// AvailableAfterClusterUpgradeTo is set to the cluster's upgrade target
+	// if it supports bundles that match.
+	AvailableAfterClusterUpgradeTo string
+	// ExcludedVersions are the versions of the bundles that match but are
+	// excluded by VersionExcludes.
+	ExcludedVersions []string
+}
+
+func (e *PackageNotFoundError) Error() string {
 	// TODO: update this error message when/if we decide to support version ranges as opposed to fixing the version
 	//  context: we originally wanted to support version ranges and take the highest version that satisfies the range
 	//  during the upstream call on the 2023-04-11 we decided to pin the version instead. But, we'll keep version range
 	//  support under the covers in case we decide to pivot back.
-	if r.versionRange != "" && r.channelName != "" {
-		return fmt.Errorf("package '%s' at version '%s' in channel '%s' not found", r.packageName, r.versionRange, r.channelName)
+	msg := fmt.Sprintf("package '%s'", e.PackageName)
+	if e.VersionRange != "" {
+		msg += fmt.Sprintf(" at version '%s'", e.VersionRange)
+	}
+	if len(e.VersionPreference) > 0 {
+		msg += fmt.Sprintf(" at any version in '%s'", strings.Join(e.VersionPreference, "', '"))
+	}
+	if len(e.VersionExcludes) > 0 {
+		msg += fmt.Sprintf(" excluding versions in '%s'", strings.Join(e.VersionExcludes, "', '"))
+	}
+	if e.Channel != "" {
+		msg += fmt.Sprintf(" in channel '%s'", e.Channel)
+	}
+	if len(e.Channels) > 0 {
+		msg += fmt.Sprintf(" in any channel of '%s'", strings.Join(e.Channels, "', '"))
+	}
+	if e.CatalogName != "" {
+		msg += fmt.Sprintf(" in catalog '%s'", e.CatalogName)
+	}
+	if e.CatalogRef != "" {
+		msg += fmt.Sprintf(" at catalog ref '%s'", e.CatalogRef)
+	}
+	if e.ZStreamOf != "" {
+		msg += fmt.Sprintf(" in the z-stream of version '%s'", e.ZStreamOf)
+	}
+	if len(e.Predicates) > 0 {
+		msg += fmt.Sprintf(" matching '%s'", strings.Join(e.Predicates, "', '"))
+	}
+	if e.KubeVersion != "" {
+		msg += fmt.Sprintf(" for kubernetes version '%s'", e.KubeVersion)
 	}
-	if r.versionRange != "" {
-		return fmt.Errorf("package '%s' at version '%s' not found", r.packageName, r.versionRange)
+	msg += " not found"
+	if len(e.ExcludedVersions) > 0 {
+		msg += fmt.Sprintf("; only excluded versions match: %s", strings.Join(e.ExcludedVersions, ", "))
 	}
-	if r.channelName != "" {
-		return fmt.Errorf("package '%s' in channel '%s' not found", r.packageName, r.channelName)
+	if e.AvailableAfterClusterUpgradeTo != "" {
+		msg += fmt.Sprintf("; available after cluster upgrade to kubernetes version '%s'", e.AvailableAfterClusterUpgradeTo)
 	}
-	return fmt.Errorf("package '%s' not found", r.packageName)
+	return msg
 }
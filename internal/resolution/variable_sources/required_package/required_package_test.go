@@ -2,9 +2,11 @@ package required_package_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/blang/semver/v4"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/operator-framework/deppy/pkg/deppy"
@@ -144,6 +146,92 @@ var _ = Describe("RequiredPackageVariableSource", func() {
 		}))
 	})
 
+	It("should order bundles by version preference", func() {
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.PreferVersionRanges("1.x", ">=2.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		var ids []deppy.Identifier
+		for _, bundle := range reqPackageVar.BundleEntities() {
+			ids = append(ids, bundle.ID)
+		}
+		Expect(ids).To(Equal([]deppy.Identifier{"bundle-1", "bundle-2", "bundle-3"}))
+	})
+
+	It("should include the version preference in the not found error", func() {
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.PreferVersionRanges("4.x", "5.x"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(MatchError("package 'test-package' at any version in '4.x', '5.x' not found"))
+	})
+
+	It("should exclude versions", func() {
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.ExcludingVersions("3.0.0", "<2.0.0"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		var ids []deppy.Identifier
+		for _, bundle := range reqPackageVar.BundleEntities() {
+			ids = append(ids, bundle.ID)
+		}
+		Expect(ids).To(Equal([]deppy.Identifier{"bundle-3"}))
+	})
+
+	It("should report the excluded versions when only excluded versions match", func() {
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName,
+			required_package.InVersionRange(">=2.0.0"),
+			required_package.ExcludingVersions("2.x", "3.x"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(MatchError("package 'test-package' at version '>=2.0.0' excluding versions in '2.x', '3.x' not found; only excluded versions match: 3.0.0, 2.0.0"))
+	})
+
+	It("should order bundles by channel preference", func() {
+		mockEntitySource := input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+			"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+				property.TypePackage: `{"packageName": "test-package", "version": "1.0.0"}`,
+				property.TypeChannel: `{"channelName":"stable","priority":0}`,
+			}),
+			"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+				property.TypePackage: `{"packageName": "test-package", "version": "2.0.0"}`,
+				property.TypeChannel: `{"channelName":"fast","priority":0}`,
+			}),
+			"bundle-3": *input.NewEntity("bundle-3", map[string]string{
+				property.TypePackage: `{"packageName": "test-package", "version": "3.0.0"}`,
+				property.TypeChannel: `{"channelName":"candidate","priority":0}`,
+			}),
+		})
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.InChannels("stable", "fast"))
+		Expect(err).NotTo(HaveOccurred())
+
+		variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).NotTo(HaveOccurred())
+		reqPackageVar, ok := variables[0].(*required_package.RequiredPackageVariable)
+		Expect(ok).To(BeTrue())
+		var ids []deppy.Identifier
+		for _, bundle := range reqPackageVar.BundleEntities() {
+			ids = append(ids, bundle.ID)
+		}
+		Expect(ids).To(Equal([]deppy.Identifier{"bundle-1", "bundle-2"}))
+
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.InChannels("beta", "alpha"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(MatchError("package 'test-package' in any channel of 'beta', 'alpha' not found"))
+	})
+
 	It("should fail with bad semver range", func() {
 		_, err := required_package.NewRequiredPackage(packageName, required_package.InVersionRange("not a valid semver"))
 		Expect(err).To(HaveOccurred())
@@ -154,5 +242,65 @@ var _ = Describe("RequiredPackageVariableSource", func() {
 		_, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(Equal("package 'test-package' not found"))
+		var notFoundErr *required_package.PackageNotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeTrue())
+		Expect(notFoundErr.PackageName).To(Equal(packageName))
+	})
+
+	It("should fail with a catalog ref but no catalog name", func() {
+		_, err := required_package.NewRequiredPackage(packageName, required_package.InCatalog("", "quay.io/operatorhubio/catalog@sha256:abc"))
+		Expect(err).To(MatchError("catalog ref 'quay.io/operatorhubio/catalog@sha256:abc' requires a catalog name"))
+	})
+
+	It("should include the catalog in the not found error", func() {
+		var err error
+		rpvs, err = required_package.NewRequiredPackage(packageName, required_package.InCatalog("operatorhub", "quay.io/operatorhubio/catalog@sha256:abc"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+		Expect(err).To(MatchError("package 'test-package' in catalog 'operatorhub' at catalog ref 'quay.io/operatorhubio/catalog@sha256:abc' not found"))
+	})
+
+	Describe("CompatibleWithKubeVersion", func() {
+		BeforeEach(func() {
+			mockEntitySource = input.NewCacheQuerier(map[deppy.Identifier]input.Entity{
+				"bundle-1": *input.NewEntity("bundle-1", map[string]string{
+					property.TypePackage: `{"packageName": "test-package", "version": "1.0.0"}`,
+					property.TypeChannel: `{"channelName":"stable","priority":0}`,
+				}),
+				"bundle-2": *input.NewEntity("bundle-2", map[string]string{
+					property.TypePackage:                   `{"packageName": "test-package", "version": "2.0.0"}`,
+					property.TypeChannel:                   `{"channelName":"stable","priority":0}`,
+					olmentity.PropertyBundleMinKubeVersion: `"1.28.0"`,
+				}),
+				"bundle-3": *input.NewEntity("bundle-3", map[string]string{
+					property.TypePackage:                   `{"packageName": "test-package", "version": "3.0.0"}`,
+					property.TypeChannel:                   `{"channelName":"stable","priority":0}`,
+					olmentity.PropertyBundleMinKubeVersion: `"1.30.0"`,
+				}),
+			})
+		})
+
+		It("should only return bundles that support the kube version", func() {
+			target := semver.MustParse("1.28.0")
+			rpvs, err := required_package.NewRequiredPackage(packageName, required_package.CompatibleWithKubeVersion(semver.MustParse("1.27.4"), &target))
+			Expect(err).NotTo(HaveOccurred())
+			variables, err := rpvs.GetVariables(context.TODO(), mockEntitySource)
+			Expect(err).NotTo(HaveOccurred())
+			reqPackageVar := variables[0].(*required_package.RequiredPackageVariable)
+			Expect(reqPackageVar.BundleEntities()).To(HaveLen(1))
+			Expect(reqPackageVar.BundleEntities()[0].ID).To(Equal(deppy.Identifier("bundle-1")))
+			Expect(reqPackageVar.AvailableAfterClusterUpgrade()).To(HaveLen(1))
+			Expect(reqPackageVar.AvailableAfterClusterUpgrade()[0].ID).To(Equal(deppy.Identifier("bundle-2")))
+		})
+
+		It("should report bundles available after the cluster upgrade in the not found error", func() {
+			target := semver.MustParse("1.30.0")
+			rpvs, err := required_package.NewRequiredPackage(packageName,
+				required_package.InVersionRange(">=2.0.0"),
+				required_package.CompatibleWithKubeVersion(semver.MustParse("1.27.4"), &target))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = rpvs.GetVariables(context.TODO(), mockEntitySource)
+			Expect(err).To(MatchError("package 'test-package' at version '>=2.0.0' for kubernetes version '1.27.4' not found; available after cluster upgrade to kubernetes version '1.30.0'"))
+		})
 	})
 })
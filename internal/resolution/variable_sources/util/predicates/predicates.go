@@ -40,6 +40,58 @@ func InChannel(channelName string) input.Predicate {
 	}
 }
 
+// InCatalog matches entities sourced from the named catalog. If ref is not
+// empty, the entity must also have been sourced from that snapshot of the
+// catalog's content.
+func InCatalog(name, ref string) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		catalog, err := bundleEntity.Catalog()
+		if err != nil {
+			return false
+		}
+		return catalog.Name == name && (ref == "" || catalog.Ref == ref)
+	}
+}
+
+// InZStreamOf matches entities with the major and minor version of version
+// that are not older than it.
+func InZStreamOf(version semver.Version) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		bundleVersion, err := bundleEntity.Version()
+		if err != nil {
+			return false
+		}
+		return bundleVersion.Major == version.Major && bundleVersion.Minor == version.Minor && bundleVersion.GTE(version)
+	}
+}
+
+// WithBundlePath matches entities with the given bundle path.
+func WithBundlePath(bundlePath string) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		path, err := bundleEntity.BundlePath()
+		if err != nil {
+			return false
+		}
+		return path == bundlePath
+	}
+}
+
+// CompatibleWithKubeVersion matches entities that support kubeVersion, i.e.
+// that don't declare a minimum Kubernetes version above it.
+func CompatibleWithKubeVersion(kubeVersion semver.Version) input.Predicate {
+	return func(entity *input.Entity) bool {
+		bundleEntity := olmentity.NewBundleEntity(entity)
+		minKubeVersion, err := bundleEntity.MinKubeVersion()
+		if err != nil {
+			return false
+		}
+		return minKubeVersion == nil || minKubeVersion.LTE(kubeVersion)
+	}
+}
+
 func ProvidesGVK(gvk *olmentity.GVK) input.Predicate {
 	return func(entity *input.Entity) bool {
 		bundleEntity := olmentity.NewBundleEntity(entity)
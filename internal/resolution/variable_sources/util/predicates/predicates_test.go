@@ -51,6 +51,53 @@ var _ = Describe("Predicates", func() {
 		})
 	})
 
+	Describe("InCatalog", func() {
+		It("should return true when the entity comes from the specified catalog", func() {
+			entity := input.NewEntity("test", map[string]string{
+				olmentity.PropertyBundleCatalog: `{"name":"operatorhub","ref":"quay.io/operatorhubio/catalog@sha256:abc"}`,
+			})
+			Expect(predicates.InCatalog("operatorhub", "")(entity)).To(BeTrue())
+			Expect(predicates.InCatalog("operatorhub", "quay.io/operatorhubio/catalog@sha256:abc")(entity)).To(BeTrue())
+			Expect(predicates.InCatalog("operatorhub", "quay.io/operatorhubio/catalog@sha256:def")(entity)).To(BeFalse())
+			Expect(predicates.InCatalog("other", "")(entity)).To(BeFalse())
+		})
+	})
+
+	Describe("InZStreamOf", func() {
+		It("should return true when the entity is a z-stream upgrade of the version", func() {
+			entity := input.NewEntity("test", map[string]string{
+				property.TypePackage: `{"packageName": "mypackage", "version": "1.2.3"}`,
+			})
+			Expect(predicates.InZStreamOf(semver.MustParse("1.2.0"))(entity)).To(BeTrue())
+			Expect(predicates.InZStreamOf(semver.MustParse("1.2.3"))(entity)).To(BeTrue())
+			Expect(predicates.InZStreamOf(semver.MustParse("1.2.4"))(entity)).To(BeFalse())
+			Expect(predicates.InZStreamOf(semver.MustParse("1.1.0"))(entity)).To(BeFalse())
+			Expect(predicates.InZStreamOf(semver.MustParse("2.2.0"))(entity)).To(BeFalse())
+		})
+	})
+
+	Describe("WithBundlePath", func() {
+		It("should return true when the entity has the bundle path", func() {
+			entity := input.NewEntity("test", map[string]string{
+				olmentity.PropertyBundlePath: `"foo.io/bar:v1"`,
+			})
+			Expect(predicates.WithBundlePath("foo.io/bar:v1")(entity)).To(BeTrue())
+			Expect(predicates.WithBundlePath("foo.io/bar:v2")(entity)).To(BeFalse())
+		})
+	})
+
+	Describe("CompatibleWithKubeVersion", func() {
+		It("should return true when the entity supports the kube version", func() {
+			entity := input.NewEntity("test", map[string]string{
+				olmentity.PropertyBundleMinKubeVersion: `"1.27.0"`,
+			})
+			Expect(predicates.CompatibleWithKubeVersion(semver.MustParse("1.27.0"))(entity)).To(BeTrue())
+			Expect(predicates.CompatibleWithKubeVersion(semver.MustParse("1.28.1"))(entity)).To(BeTrue())
+			Expect(predicates.CompatibleWithKubeVersion(semver.MustParse("1.26.5"))(entity)).To(BeFalse())
+			Expect(predicates.CompatibleWithKubeVersion(semver.MustParse("1.26.5"))(input.NewEntity("test", map[string]string{}))).To(BeTrue())
+		})
+	})
+
 	Describe("ProvidesGVK", func() {
 		It("should return true when the entity provides the specified gvk", func() {
 			entity := input.NewEntity("test", map[string]string{
@@ -0,0 +1,142 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clientutil provides helpers for programs that create Operators and
+// need to act on their status, such as tests, CLIs and other controllers.
+package clientutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+)
+
+// The labels rukpak sets on every object it manages for a BundleDeployment.
+// rukpak declares them in an internal package, so they are repeated here.
+const (
+	ownerKindLabel = "core.rukpak.io/owner-kind"
+	ownerNameLabel = "core.rukpak.io/owner-name"
+)
+
+// ErrNotInstalled is returned when an Operator has not installed a bundle.
+var ErrNotInstalled = errors.New("operator is not installed")
+
+// IsInstalled returns true if the Operator's Installed condition is True and
+// was observed for its current generation.
+func IsInstalled(op *operatorsv1alpha1.Operator) bool {
+	cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled)
+	return cond != nil && cond.Status == metav1.ConditionTrue && cond.ObservedGeneration == op.GetGeneration()
+}
+
+// WaitForOperatorInstalled polls the Operator with the given name every
+// interval until it is installed or ctx is done. Failed installations are
+// retried by the controller, so they do not end the wait; the error returned
+// when ctx is done includes the last Installed condition message.
+func WaitForOperatorInstalled(ctx context.Context, c client.Reader, name string, interval time.Duration) (*operatorsv1alpha1.Operator, error) {
+	op := &operatorsv1alpha1.Operator{}
+	var lastErr error
+	err := wait.PollImmediateUntilWithContext(ctx, interval, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, op); err != nil {
+			lastErr = err
+			return false, nil
+		}
+		lastErr = nil
+		return IsInstalled(op), nil
+	})
+	if err == nil {
+		return op, nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("waiting for operator %q to be installed: %w", name, lastErr)
+	}
+	msg := "no Installed condition"
+	if cond := apimeta.FindStatusCondition(op.Status.Conditions, operatorsv1alpha1.TypeInstalled); cond != nil {
+		msg = fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+	}
+	return nil, fmt.Errorf("waiting for operator %q to be installed: %w (%s)", name, err, msg)
+}
+
+// GetInstalledBundle returns the provenance of the bundle installed by the
+// Operator with the given name, or ErrNotInstalled if it has not installed one.
+func GetInstalledBundle(ctx context.Context, c client.Reader, name string) (*operatorsv1alpha1.BundleProvenance, error) {
+	op := &operatorsv1alpha1.Operator{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, op); err != nil {
+		return nil, err
+	}
+	if op.Status.InstalledBundleResource == "" {
+		return nil, ErrNotInstalled
+	}
+	if op.Status.InstalledBundleProvenance != nil {
+		return op.Status.InstalledBundleProvenance, nil
+	}
+	// Bundles that were not installed from an image have no provenance.
+	return &operatorsv1alpha1.BundleProvenance{Image: op.Status.InstalledBundleResource}, nil
+}
+
+// GetBundleDeployment returns the BundleDeployment the Operator with the
+// given name manages. The objects in the bundle are managed by rukpak on
+// behalf of the BundleDeployment.
+func GetBundleDeployment(ctx context.Context, c client.Reader, name string) (*rukpakv1alpha1.BundleDeployment, error) {
+	op := &operatorsv1alpha1.Operator{}
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, op); err != nil {
+		return nil, err
+	}
+	bd := &rukpakv1alpha1.BundleDeployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: op.GetName()}, bd); err != nil {
+		return nil, err
+	}
+	if !metav1.IsControlledBy(bd, op) {
+		return nil, fmt.Errorf("bundledeployment %q is not managed by operator %q", bd.GetName(), op.GetName())
+	}
+	return bd, nil
+}
+
+// ListManagedObjects returns the metadata of the objects of the given kinds
+// that rukpak manages for the Operator with the given name. The
+// BundleDeployment status does not record the objects it manages, so they are
+// found by the owner labels rukpak sets on them, and only the given kinds are
+// searched.
+func ListManagedObjects(ctx context.Context, c client.Reader, name string, gvks ...schema.GroupVersionKind) ([]metav1.PartialObjectMetadata, error) {
+	bd, err := GetBundleDeployment(ctx, c, name)
+	if err != nil {
+		return nil, err
+	}
+	selector := client.MatchingLabels{
+		ownerKindLabel: rukpakv1alpha1.BundleDeploymentKind,
+		ownerNameLabel: bd.GetName(),
+	}
+	var objs []metav1.PartialObjectMetadata
+	for _, gvk := range gvks {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.List(ctx, list, selector); err != nil {
+			return nil, fmt.Errorf("listing %s managed by bundledeployment %q: %w", gvk.Kind, bd.GetName(), err)
+		}
+		objs = append(objs, list.Items...)
+	}
+	return objs, nil
+}
@@ -0,0 +1,175 @@
+package clientutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rukpakv1alpha1 "github.com/operator-framework/rukpak/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	operatorsv1alpha1 "github.com/operator-framework/operator-controller/api/v1alpha1"
+	"github.com/operator-framework/operator-controller/pkg/clientutil"
+)
+
+func TestClientUtil(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ClientUtil Suite")
+}
+
+func newClient(objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	utilruntime.Must(operatorsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(rukpakv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func installedOperator(name, image string) *operatorsv1alpha1.Operator {
+	op := &operatorsv1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1, UID: types.UID("uid-" + name)},
+		Spec:       operatorsv1alpha1.OperatorSpec{PackageName: name},
+	}
+	op.Status.InstalledBundleResource = image
+	apimeta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+		Type:               operatorsv1alpha1.TypeInstalled,
+		Status:             metav1.ConditionTrue,
+		Reason:             operatorsv1alpha1.ReasonSuccess,
+		ObservedGeneration: 1,
+	})
+	return op
+}
+
+var _ = Describe("IsInstalled", func() {
+	It("is true for an Installed condition observed for the current generation", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		Expect(clientutil.IsInstalled(op)).To(BeTrue())
+	})
+	It("is false for an Installed condition observed for an older generation", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		op.Generation = 2
+		Expect(clientutil.IsInstalled(op)).To(BeFalse())
+	})
+	It("is false without an Installed condition", func() {
+		Expect(clientutil.IsInstalled(&operatorsv1alpha1.Operator{})).To(BeFalse())
+	})
+})
+
+var _ = Describe("WaitForOperatorInstalled", func() {
+	It("returns the Operator once it is installed", func() {
+		c := newClient(installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc"))
+		op, err := clientutil.WaitForOperatorInstalled(context.Background(), c, "prometheus", time.Millisecond)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(op.GetName()).To(Equal("prometheus"))
+	})
+	It("reports the last Installed condition when the context is done", func() {
+		op := installedOperator("prometheus", "")
+		apimeta.SetStatusCondition(&op.Status.Conditions, metav1.Condition{
+			Type:               operatorsv1alpha1.TypeInstalled,
+			Status:             metav1.ConditionFalse,
+			Reason:             operatorsv1alpha1.ReasonInstallationFailed,
+			Message:            "bundledeployment not ready",
+			ObservedGeneration: 1,
+		})
+		c := newClient(op)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := clientutil.WaitForOperatorInstalled(ctx, c, "prometheus", time.Millisecond)
+		Expect(err).To(MatchError(ContainSubstring("InstallationFailed: bundledeployment not ready")))
+	})
+})
+
+var _ = Describe("GetInstalledBundle", func() {
+	It("returns the installed bundle's provenance", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		op.Status.InstalledBundleProvenance = &operatorsv1alpha1.BundleProvenance{
+			Image:   "quay.io/operatorhubio/prometheus@sha256:abc",
+			Digest:  "sha256:abc",
+			Catalog: "operatorhub",
+		}
+		provenance, err := clientutil.GetInstalledBundle(context.Background(), newClient(op), "prometheus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(provenance).To(Equal(op.Status.InstalledBundleProvenance))
+	})
+	It("returns ErrNotInstalled if no bundle is installed", func() {
+		op := installedOperator("prometheus", "")
+		_, err := clientutil.GetInstalledBundle(context.Background(), newClient(op), "prometheus")
+		Expect(errors.Is(err, clientutil.ErrNotInstalled)).To(BeTrue())
+	})
+})
+
+var _ = Describe("GetBundleDeployment", func() {
+	It("returns the BundleDeployment controlled by the Operator", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "prometheus",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: operatorsv1alpha1.GroupVersion.String(),
+				Kind:       "Operator",
+				Name:       op.GetName(),
+				UID:        op.GetUID(),
+				Controller: pointer.Bool(true),
+			}},
+		}}
+		got, err := clientutil.GetBundleDeployment(context.Background(), newClient(op, bd), "prometheus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got.GetName()).To(Equal("prometheus"))
+	})
+	It("fails if the BundleDeployment is not controlled by the Operator", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: "prometheus"}}
+		_, err := clientutil.GetBundleDeployment(context.Background(), newClient(op, bd), "prometheus")
+		Expect(err).To(MatchError(`bundledeployment "prometheus" is not managed by operator "prometheus"`))
+	})
+})
+
+var _ = Describe("ListManagedObjects", func() {
+	managedConfigMap := func(name, owner string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "monitoring",
+			Labels: map[string]string{
+				"core.rukpak.io/owner-kind": "BundleDeployment",
+				"core.rukpak.io/owner-name": owner,
+			},
+		}}
+	}
+	It("returns the objects of the given kinds labeled for the Operator's BundleDeployment", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{
+			Name: "prometheus",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: operatorsv1alpha1.GroupVersion.String(),
+				Kind:       "Operator",
+				Name:       op.GetName(),
+				UID:        op.GetUID(),
+				Controller: pointer.Bool(true),
+			}},
+		}}
+		unlabeled := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", Namespace: "monitoring"}}
+		c := newClient(op, bd, managedConfigMap("managed", "prometheus"), managedConfigMap("other", "etcd"), unlabeled)
+
+		objs, err := clientutil.ListManagedObjects(context.Background(), c, "prometheus", corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(1))
+		Expect(objs[0].GetName()).To(Equal("managed"))
+		Expect(objs[0].GetNamespace()).To(Equal("monitoring"))
+	})
+	It("fails if the BundleDeployment is not controlled by the Operator", func() {
+		op := installedOperator("prometheus", "quay.io/operatorhubio/prometheus@sha256:abc")
+		bd := &rukpakv1alpha1.BundleDeployment{ObjectMeta: metav1.ObjectMeta{Name: "prometheus"}}
+		_, err := clientutil.ListManagedObjects(context.Background(), newClient(op, bd), "prometheus", corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		Expect(err).To(MatchError(`bundledeployment "prometheus" is not managed by operator "prometheus"`))
+	})
+})
@@ -0,0 +1,365 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compat reports whether the channel heads of a file-based catalog
+// can be installed by operator-controller, so that catalog maintainers can
+// prioritize fixes before their catalog is served on a cluster.
+package compat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	catalogd "github.com/operator-framework/catalogd/api/core/v1alpha1"
+	"github.com/operator-framework/operator-registry/alpha/property"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/operator-framework/operator-controller/internal/resolution/variable_sources/entity"
+)
+
+const (
+	schemaPackage = "olm.package"
+	schemaChannel = "olm.channel"
+	schemaBundle  = "olm.bundle"
+
+	propertyCSVMetadata = "olm.csv.metadata"
+)
+
+// Catalog is the content of a file-based catalog that the report is built from.
+type Catalog struct {
+	Packages []string
+	Channels []Channel
+	Bundles  map[string]Bundle
+}
+
+// Channel is an olm.channel of a file-based catalog.
+type Channel struct {
+	Name    string                  `json:"name"`
+	Package string                  `json:"package"`
+	Entries []catalogd.ChannelEntry `json:"entries"`
+}
+
+// Bundle is an olm.bundle of a file-based catalog.
+type Bundle struct {
+	Name       string              `json:"name"`
+	Package    string              `json:"package"`
+	Image      string              `json:"image"`
+	Properties []property.Property `json:"properties,omitempty"`
+
+	// fsys and dir are the catalog and the directory of the file the bundle
+	// was loaded from, which olm.bundle.object references are relative to.
+	fsys fs.FS
+	dir  string
+}
+
+// Report is the compatibility report of a catalog, ordered by package name.
+type Report struct {
+	Packages []PackageReport `json:"packages"`
+}
+
+// PackageReport is the compatibility report of a package's channel heads,
+// ordered by bundle name.
+type PackageReport struct {
+	Name  string         `json:"name"`
+	Heads []BundleReport `json:"heads"`
+}
+
+// BundleReport is the compatibility report of a bundle.
+type BundleReport struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Channels are the channels the bundle is the head of.
+	Channels []string `json:"channels"`
+	// Installable is whether operator-controller can install the bundle.
+	Installable bool `json:"installable"`
+	// Problems explain why the bundle is not installable.
+	Problems []string `json:"problems,omitempty"`
+	// NeedsWebhooks is whether the bundle's ClusterServiceVersion defines
+	// webhooks. It is only known for bundles that carry their objects in
+	// olm.bundle.object properties.
+	NeedsWebhooks bool `json:"needsWebhooks"`
+	// Dependencies are the packages and APIs the bundle requires.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// Load reads the file-based catalog in the JSON and YAML files of fsys.
+func Load(fsys fs.FS) (*Catalog, error) {
+	catalog := &Catalog{Bundles: map[string]Bundle{}}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(p) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := catalog.load(f, fsys, path.Dir(p)); err != nil {
+			return fmt.Errorf("loading %s: %w", p, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(catalog.Packages)
+	return catalog, nil
+}
+
+func (c *Catalog) load(r io.Reader, fsys fs.FS, dir string) error {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var meta struct {
+			Schema string `json:"schema"`
+			Name   string `json:"name"`
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return err
+		}
+		switch meta.Schema {
+		case schemaPackage:
+			c.Packages = append(c.Packages, meta.Name)
+		case schemaChannel:
+			ch := Channel{}
+			if err := json.Unmarshal(raw, &ch); err != nil {
+				return fmt.Errorf("invalid channel %q: %w", meta.Name, err)
+			}
+			c.Channels = append(c.Channels, ch)
+		case schemaBundle:
+			b := Bundle{}
+			if err := json.Unmarshal(raw, &b); err != nil {
+				return fmt.Errorf("invalid bundle %q: %w", meta.Name, err)
+			}
+			b.fsys, b.dir = fsys, dir
+			c.Bundles[b.Name] = b
+		}
+	}
+}
+
+// Report checks the head bundle of every channel in the catalog.
+func (c *Catalog) Report() Report {
+	heads := map[string]map[string][]string{}
+	for _, ch := range c.Channels {
+		if heads[ch.Package] == nil {
+			heads[ch.Package] = map[string][]string{}
+		}
+		for _, head := range channelHeads(ch) {
+			heads[ch.Package][head] = append(heads[ch.Package][head], ch.Name)
+		}
+	}
+
+	report := Report{Packages: []PackageReport{}}
+	for _, pkg := range c.Packages {
+		pkgReport := PackageReport{Name: pkg, Heads: []BundleReport{}}
+		for name, channels := range heads[pkg] {
+			sort.Strings(channels)
+			pkgReport.Heads = append(pkgReport.Heads, c.bundleReport(pkg, name, channels))
+		}
+		sort.Slice(pkgReport.Heads, func(i, j int) bool { return pkgReport.Heads[i].Name < pkgReport.Heads[j].Name })
+		report.Packages = append(report.Packages, pkgReport)
+	}
+	return report
+}
+
+// channelHeads returns the entries of ch that no other entry replaces or skips.
+func channelHeads(ch Channel) []string {
+	replaced := map[string]bool{}
+	for _, e := range ch.Entries {
+		replaced[e.Replaces] = true
+		for _, skip := range e.Skips {
+			replaced[skip] = true
+		}
+	}
+	var heads []string
+	for _, e := range ch.Entries {
+		if !replaced[e.Name] {
+			heads = append(heads, e.Name)
+		}
+	}
+	return heads
+}
+
+func (c *Catalog) bundleReport(pkg, name string, channels []string) BundleReport {
+	report := BundleReport{Name: name, Channels: channels}
+	problem := func(format string, args ...interface{}) {
+		report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+	}
+	bundle, ok := c.Bundles[name]
+	if !ok || bundle.Package != pkg {
+		problem("bundle is not in the catalog")
+		return report
+	}
+	if bundle.Image == "" {
+		problem("bundle has no image")
+	}
+
+	mediaType := entity.MediaTypeRegistry
+	// The install modes and API services are known from the bundle's CSV
+	// metadata or its ClusterServiceVersion object, if it has either.
+	var installModes []installMode
+	knownInstallModes, ownsAPIServices := false, false
+	foundPackage := false
+	for _, prop := range bundle.Properties {
+		switch prop.Type {
+		case property.TypePackage:
+			foundPackage = true
+			p := property.Package{}
+			if err := json.Unmarshal(prop.Value, &p); err != nil {
+				problem("invalid %s property: %v", property.TypePackage, err)
+				continue
+			}
+			report.Version = p.Version
+			if _, err := semver.Parse(p.Version); err != nil {
+				problem("invalid version %q: %v", p.Version, err)
+			}
+		case entity.PropertyBundleMediaType:
+			if err := json.Unmarshal(prop.Value, &mediaType); err != nil {
+				problem("invalid %s property: %v", entity.PropertyBundleMediaType, err)
+			}
+		case property.TypePackageRequired:
+			p := property.PackageRequired{}
+			if err := json.Unmarshal(prop.Value, &p); err != nil {
+				problem("invalid %s property: %v", property.TypePackageRequired, err)
+				continue
+			}
+			report.Dependencies = append(report.Dependencies, fmt.Sprintf("package %s %s", p.PackageName, p.VersionRange))
+		case property.TypeGVKRequired:
+			p := property.GVKRequired{}
+			if err := json.Unmarshal(prop.Value, &p); err != nil {
+				problem("invalid %s property: %v", property.TypeGVKRequired, err)
+				continue
+			}
+			report.Dependencies = append(report.Dependencies, fmt.Sprintf("api %s/%s %s", p.Group, p.Version, p.Kind))
+		case propertyCSVMetadata:
+			metadata := struct {
+				InstallModes          []installMode         `json:"installModes,omitempty"`
+				APIServiceDefinitions apiServiceDefinitions `json:"apiServiceDefinitions,omitempty"`
+			}{}
+			if err := json.Unmarshal(prop.Value, &metadata); err != nil {
+				problem("invalid %s property: %v", propertyCSVMetadata, err)
+				continue
+			}
+			installModes, knownInstallModes = metadata.InstallModes, true
+			ownsAPIServices = len(metadata.APIServiceDefinitions.Owned) > 0
+		case property.TypeBundleObject:
+			obj := property.BundleObject{}
+			if err := json.Unmarshal(prop.Value, &obj); err != nil {
+				problem("invalid %s property: %v", property.TypeBundleObject, err)
+				continue
+			}
+			if obj.IsRef() && bundle.fsys == nil {
+				problem("cannot read %s reference %q outside of a loaded catalog", property.TypeBundleObject, obj.GetRef())
+				continue
+			}
+			data, err := obj.GetData(bundle.fsys, bundle.dir)
+			if err != nil {
+				problem("cannot read %s reference %q: %v", property.TypeBundleObject, obj.GetRef(), err)
+				continue
+			}
+			if csv, ok := bundleObjectCSV(data); ok {
+				installModes, knownInstallModes = csv.Spec.InstallModes, true
+				ownsAPIServices = len(csv.Spec.APIServiceDefinitions.Owned) > 0
+				report.NeedsWebhooks = len(csv.Spec.WebhookDefinitions) > 0
+			}
+		}
+	}
+	if !foundPackage {
+		problem("missing %s property", property.TypePackage)
+	}
+
+	switch mediaType {
+	case entity.MediaTypePlain:
+	case entity.MediaTypeRegistry, "":
+		// These are the registry+v1 features rukpak can't convert.
+		if knownInstallModes && !supportsAllNamespaces(installModes) {
+			problem("does not support the AllNamespaces install mode")
+		}
+		if ownsAPIServices {
+			problem("owns API services, which are not supported")
+		}
+		if report.NeedsWebhooks {
+			problem("defines webhooks, which are not supported")
+		}
+	default:
+		problem("unsupported media type %q", mediaType)
+	}
+	sort.Strings(report.Dependencies)
+	report.Installable = len(report.Problems) == 0
+	return report
+}
+
+// installMode is an install mode of a ClusterServiceVersion.
+type installMode struct {
+	Type      string `json:"type"`
+	Supported bool   `json:"supported"`
+}
+
+// apiServiceDefinitions are the API services of a ClusterServiceVersion.
+type apiServiceDefinitions struct {
+	Owned []json.RawMessage `json:"owned,omitempty"`
+}
+
+// clusterServiceVersion holds the fields of a ClusterServiceVersion that
+// affect whether its bundle can be converted.
+type clusterServiceVersion struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		InstallModes          []installMode         `json:"installModes,omitempty"`
+		APIServiceDefinitions apiServiceDefinitions `json:"apiservicedefinitions,omitempty"`
+		WebhookDefinitions    []json.RawMessage     `json:"webhookdefinitions,omitempty"`
+	} `json:"spec"`
+}
+
+func supportsAllNamespaces(modes []installMode) bool {
+	for _, mode := range modes {
+		if mode.Type == "AllNamespaces" && mode.Supported {
+			return true
+		}
+	}
+	return false
+}
+
+// bundleObjectCSV returns the ClusterServiceVersion in data, if it is one.
+// Referenced objects may be YAML, so data is converted to JSON first.
+func bundleObjectCSV(data []byte) (*clusterServiceVersion, bool) {
+	data, err := utilyaml.ToJSON(data)
+	if err != nil {
+		return nil, false
+	}
+	csv := &clusterServiceVersion{}
+	if err := json.Unmarshal(data, csv); err != nil || csv.Kind != "ClusterServiceVersion" {
+		return nil, false
+	}
+	return csv, true
+}
@@ -0,0 +1,167 @@
+package compat_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/operator-framework/operator-controller/pkg/compat"
+)
+
+func TestCompat(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Compat Suite")
+}
+
+const catalogJSON = `
+{"schema": "olm.package", "name": "prometheus", "defaultChannel": "beta"}
+{"schema": "olm.channel", "name": "beta", "package": "prometheus", "entries": [
+  {"name": "prometheus.v0.37.0"},
+  {"name": "prometheus.v0.47.0", "replaces": "prometheus.v0.37.0"}
+]}
+{"schema": "olm.channel", "name": "stable", "package": "prometheus", "entries": [
+  {"name": "prometheus.v0.47.0"}
+]}
+{"schema": "olm.bundle", "name": "prometheus.v0.37.0", "package": "prometheus", "image": "quay.io/operatorhubio/prometheus:v0.37.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "prometheus", "version": "0.37.0"}}
+]}
+{"schema": "olm.bundle", "name": "prometheus.v0.47.0", "package": "prometheus", "image": "quay.io/operatorhubio/prometheus:v0.47.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "prometheus", "version": "0.47.0"}},
+  {"type": "olm.package.required", "value": {"packageName": "etcd", "versionRange": ">=1.0.0"}},
+  {"type": "olm.csv.metadata", "value": {"installModes": [{"type": "AllNamespaces", "supported": true}]}}
+]}
+`
+
+const catalogYAML = `---
+schema: olm.package
+name: plain
+---
+schema: olm.channel
+name: stable
+package: plain
+entries:
+- name: plain.v0.1.0
+---
+schema: olm.bundle
+name: plain.v0.1.0
+package: plain
+image: quay.io/operatorhub/plain:v0.1.0
+properties:
+- type: olm.package
+  value:
+    packageName: plain
+    version: 0.1.0
+- type: olm.bundle.mediatype
+  value: plain+v0
+`
+
+func webhookCatalog() string {
+	csv := base64.StdEncoding.EncodeToString([]byte(`{"kind": "ClusterServiceVersion", "spec": {
+  "installModes": [{"type": "OwnNamespace", "supported": true}],
+  "webhookdefinitions": [{"type": "ValidatingAdmissionWebhook"}]
+}}`))
+	return fmt.Sprintf(`
+{"schema": "olm.package", "name": "webhooks"}
+{"schema": "olm.channel", "name": "stable", "package": "webhooks", "entries": [{"name": "webhooks.v1.0.0"}]}
+{"schema": "olm.bundle", "name": "webhooks.v1.0.0", "package": "webhooks", "image": "quay.io/operatorhub/webhooks:v1.0.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "webhooks", "version": "1.0.0"}},
+  {"type": "olm.bundle.object", "value": {"data": %q}}
+]}
+`, csv)
+}
+
+var _ = Describe("Report", func() {
+	It("reports the channel heads of every package", func() {
+		catalog, err := compat.Load(fstest.MapFS{
+			"prometheus/catalog.json": {Data: []byte(catalogJSON)},
+			"plain/catalog.yaml":      {Data: []byte(catalogYAML)},
+			"webhooks/catalog.json":   {Data: []byte(webhookCatalog())},
+			"README.md":               {Data: []byte("not part of the catalog")},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(catalog.Report()).To(Equal(compat.Report{Packages: []compat.PackageReport{
+			{Name: "plain", Heads: []compat.BundleReport{{
+				Name:        "plain.v0.1.0",
+				Version:     "0.1.0",
+				Channels:    []string{"stable"},
+				Installable: true,
+			}}},
+			{Name: "prometheus", Heads: []compat.BundleReport{{
+				Name:         "prometheus.v0.47.0",
+				Version:      "0.47.0",
+				Channels:     []string{"beta", "stable"},
+				Installable:  true,
+				Dependencies: []string{"package etcd >=1.0.0"},
+			}}},
+			{Name: "webhooks", Heads: []compat.BundleReport{{
+				Name:          "webhooks.v1.0.0",
+				Version:       "1.0.0",
+				Channels:      []string{"stable"},
+				NeedsWebhooks: true,
+				Problems: []string{
+					"does not support the AllNamespaces install mode",
+					"defines webhooks, which are not supported",
+				},
+			}}},
+		}}))
+	})
+
+	It("reads olm.bundle.object references relative to the file of the bundle", func() {
+		catalog, err := compat.Load(fstest.MapFS{
+			"webhooks/catalog.json": {Data: []byte(`
+{"schema": "olm.package", "name": "webhooks"}
+{"schema": "olm.channel", "name": "stable", "package": "webhooks", "entries": [{"name": "webhooks.v1.0.0"}, {"name": "webhooks.v2.0.0"}]}
+{"schema": "olm.bundle", "name": "webhooks.v1.0.0", "package": "webhooks", "image": "quay.io/operatorhub/webhooks:v1.0.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "webhooks", "version": "1.0.0"}},
+  {"type": "olm.bundle.object", "value": {"ref": "objects/csv.yaml"}}
+]}
+{"schema": "olm.bundle", "name": "webhooks.v2.0.0", "package": "webhooks", "image": "quay.io/operatorhub/webhooks:v2.0.0", "properties": [
+  {"type": "olm.package", "value": {"packageName": "webhooks", "version": "2.0.0"}},
+  {"type": "olm.bundle.object", "value": {"ref": "objects/missing.yaml"}}
+]}
+`)},
+			"webhooks/objects/csv.yaml": {Data: []byte(`kind: ClusterServiceVersion
+spec:
+  installModes:
+  - type: AllNamespaces
+    supported: true
+  webhookdefinitions:
+  - type: ValidatingAdmissionWebhook
+`)},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		heads := catalog.Report().Packages[0].Heads
+		Expect(heads).To(HaveLen(2))
+		Expect(heads[0].NeedsWebhooks).To(BeTrue())
+		Expect(heads[0].Problems).To(Equal([]string{"defines webhooks, which are not supported"}))
+		Expect(heads[1].Problems).To(ConsistOf(ContainSubstring(`cannot read olm.bundle.object reference "objects/missing.yaml"`)))
+	})
+
+	It("reports heads that are missing from the catalog or malformed", func() {
+		catalog, err := compat.Load(fstest.MapFS{"catalog.json": {Data: []byte(`
+{"schema": "olm.package", "name": "broken"}
+{"schema": "olm.channel", "name": "stable", "package": "broken", "entries": [{"name": "broken.v1.0.0"}, {"name": "broken.v2.0.0"}]}
+{"schema": "olm.bundle", "name": "broken.v2.0.0", "package": "broken", "properties": [
+  {"type": "olm.package", "value": {"packageName": "broken", "version": "latest"}},
+  {"type": "olm.bundle.mediatype", "value": "helm+v3"}
+]}
+`)}})
+		Expect(err).NotTo(HaveOccurred())
+
+		heads := catalog.Report().Packages[0].Heads
+		Expect(heads).To(HaveLen(2))
+		Expect(heads[0].Problems).To(Equal([]string{"bundle is not in the catalog"}))
+		Expect(heads[1].Installable).To(BeFalse())
+		Expect(heads[1].Problems).To(ConsistOf(
+			"bundle has no image",
+			ContainSubstring(`invalid version "latest"`),
+			`unsupported media type "helm+v3"`,
+		))
+	})
+})